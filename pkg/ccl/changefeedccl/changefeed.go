@@ -117,6 +117,7 @@ func kvsToRows(
 			}
 			r.row.datums = append(sqlbase.EncDatumRow(nil), r.row.datums...)
 			r.row.deleted = rf.RowIsDeleted()
+			r.row.key = kv.Key
 			// TODO(mrtracy): This should likely be set to schemaTimestamp instead of
 			// the value timestamp, if schema timestamp is set. However, doing so
 			// seems to break some of the assumptions of our existing tests in subtle
@@ -173,6 +174,7 @@ func emitEntries(
 	watchedSpans []roachpb.Span,
 	encoder Encoder,
 	sink Sink,
+	router *zoneConstraintChangeRouter,
 	inputFn func(context.Context) ([]emitEntry, error),
 	knobs TestingKnobs,
 	metrics *Metrics,
@@ -196,7 +198,17 @@ func emitEntries(
 				return err
 			}
 		}
-		if err := sink.EmitRow(
+		if router != nil {
+			route, err := router.route(row.key)
+			if err != nil {
+				return err
+			}
+			if err := sink.(zoneRoutingSink).EmitRoutedRow(
+				ctx, row.tableDesc, keyCopy, valueCopy, row.updated, route,
+			); err != nil {
+				return err
+			}
+		} else if err := sink.EmitRow(
 			ctx, row.tableDesc, keyCopy, valueCopy, row.updated,
 		); err != nil {
 			return err
@@ -175,8 +175,12 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 	)
 	rowsFn := kvsToRows(leaseMgr, ca.spec.Feed, buf.Get)
 
+	var router *zoneConstraintChangeRouter
+	if _, ok := ca.spec.Feed.Opts[optZoneRouting]; ok {
+		router = makeZoneConstraintChangeRouter(ca.flowCtx.Gossip)
+	}
 	ca.tickFn = emitEntries(
-		ca.flowCtx.Settings, ca.spec.Feed, spans, ca.encoder, ca.sink, rowsFn, knobs, metrics)
+		ca.flowCtx.Settings, ca.spec.Feed, spans, ca.encoder, ca.sink, router, rowsFn, knobs, metrics)
 
 	// Give errCh enough buffer both possible errors from supporting goroutines,
 	// but only the first one is ever used.
@@ -42,6 +42,7 @@ func init() {
 
 type envelopeType string
 type formatType string
+type zoneRoutingType string
 
 const (
 	optConfluentSchemaRegistry = `confluent_schema_registry`
@@ -50,6 +51,7 @@ const (
 	optFormat                  = `format`
 	optResolvedTimestamps      = `resolved`
 	optUpdatedTimestamps       = `updated`
+	optZoneRouting             = `zone_routing`
 
 	optEnvelopeKeyOnly       envelopeType = `key_only`
 	optEnvelopeRow           envelopeType = `row`
@@ -59,6 +61,10 @@ const (
 	optFormatJSON formatType = `json`
 	optFormatAvro formatType = `experimental_avro`
 
+	// optZoneRoutingUnordered acknowledges that zone routing does not preserve
+	// the order of the changes to a row whose zone changes. See zoneRoute.
+	optZoneRoutingUnordered zoneRoutingType = `unordered`
+
 	sinkParamCACert           = `ca_cert`
 	sinkParamFileSize         = `file_size`
 	sinkParamSchemaTopic      = `schema_topic`
@@ -80,6 +86,7 @@ var changefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	optFormat:                  sql.KVStringOptRequireValue,
 	optResolvedTimestamps:      sql.KVStringOptAny,
 	optUpdatedTimestamps:       sql.KVStringOptRequireNoValue,
+	optZoneRouting:             sql.KVStringOptAny,
 }
 
 // changefeedPlanHook implements sql.PlanHookFn.
@@ -372,6 +379,20 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 			`unknown %s: %s`, optFormat, details.Opts[optFormat])
 	}
 
+	if r, ok := details.Opts[optZoneRouting]; ok {
+		switch zoneRoutingType(r) {
+		case optZoneRoutingUnordered:
+			// No-op.
+		case ``:
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`%s does not preserve the order of the changes to a row whose zone changes; `+
+					`use %s='%s' to accept this`, optZoneRouting, optZoneRouting, optZoneRoutingUnordered)
+		default:
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`unknown %s: %s`, optZoneRouting, r)
+		}
+	}
+
 	return details, nil
 }
 
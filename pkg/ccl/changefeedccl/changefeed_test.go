@@ -1295,6 +1295,14 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `unknown envelope: nope`,
 		`EXPERIMENTAL CHANGEFEED FOR foo WITH envelope=nope`,
 	)
+	sqlDB.ExpectErr(
+		t, `zone_routing does not preserve the order of the changes to a row whose zone changes`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH zone_routing`,
+	)
+	sqlDB.ExpectErr(
+		t, `unknown zone_routing: nope`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH zone_routing=nope`,
+	)
 	sqlDB.ExpectErr(
 		t, `negative durations are not accepted: resolved='-1s'`,
 		`EXPERIMENTAL CHANGEFEED FOR foo WITH resolved='-1s'`,
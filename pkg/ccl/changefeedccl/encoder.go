@@ -18,6 +18,7 @@ import (
 	"net/url"
 	"path/filepath"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -47,6 +48,8 @@ type encodeRow struct {
 	// tableDesc is a TableDescriptor for the table containing `datums`.
 	// It's valid for interpreting the row at `updated`.
 	tableDesc *sqlbase.TableDescriptor
+	// key is the key of the KV that `datums` was decoded from.
+	key roachpb.Key
 }
 
 // Encoder turns a row into a serialized changefeed key, value, or resolved
@@ -47,6 +47,25 @@ func (s *metricsSink) EmitRow(
 	return err
 }
 
+// EmitRoutedRow implements the zoneRoutingSink interface. It must only be
+// called if the wrapped sink implements it too.
+func (s *metricsSink) EmitRoutedRow(
+	ctx context.Context,
+	table *sqlbase.TableDescriptor,
+	key, value []byte,
+	updated hlc.Timestamp,
+	route zoneRoute,
+) error {
+	start := timeutil.Now()
+	err := s.wrapped.(zoneRoutingSink).EmitRoutedRow(ctx, table, key, value, updated, route)
+	if err == nil {
+		s.metrics.EmittedMessages.Inc(1)
+		s.metrics.EmittedBytes.Inc(int64(len(key) + len(value)))
+		s.metrics.EmitNanos.Inc(timeutil.Since(start).Nanoseconds())
+	}
+	return err
+}
+
 func (s *metricsSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
 ) error {
@@ -77,6 +77,10 @@ func getSink(
 	}
 	q := u.Query()
 
+	if _, ok := opts[optZoneRouting]; ok && u.Scheme != sinkSchemeKafka {
+		return nil, errors.Errorf(`this sink is incompatible with %s`, optZoneRouting)
+	}
+
 	// Use a function here to delay creation of the sink until after we've done
 	// all the parameter verification.
 	var makeSink func() (Sink, error)
@@ -387,6 +391,31 @@ func (s *kafkaSink) EmitRow(
 	return s.emitMessage(ctx, msg)
 }
 
+// EmitRoutedRow implements the zoneRoutingSink interface. The row is sent to
+// the partition picked by changefeedPartitioner for its route, so all rows
+// stored under the same zone constraints go to the same partition of the
+// topic.
+func (s *kafkaSink) EmitRoutedRow(
+	ctx context.Context,
+	table *sqlbase.TableDescriptor,
+	key, value []byte,
+	_ hlc.Timestamp,
+	route zoneRoute,
+) error {
+	topic := s.cfg.kafkaTopicPrefix + SQLNameToKafkaName(table.Name)
+	if _, ok := s.topics[topic]; !ok {
+		return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+	}
+
+	msg := &sarama.ProducerMessage{
+		Topic:    topic,
+		Key:      sarama.ByteEncoder(key),
+		Value:    sarama.ByteEncoder(value),
+		Metadata: route,
+	}
+	return s.emitMessage(ctx, msg)
+}
+
 // EmitResolvedTimestamp implements the Sink interface.
 func (s *kafkaSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
@@ -543,9 +572,23 @@ func (p *changefeedPartitioner) Partition(
 	if message.Key == nil {
 		return message.Partition, nil
 	}
+	if route, ok := message.Metadata.(zoneRoute); ok && route != `` {
+		return zoneRoutePartition(route, numPartitions), nil
+	}
 	return p.hash.Partition(message, numPartitions)
 }
 
+// zoneRoutePartition returns the partition that rows with the given route are
+// sent to. Rows of different zones only go to different partitions if the
+// topic has enough of them, so geo-local consumers need one partition per zone
+// and should still filter by the rows' keys if that's not true. The changes to
+// a row are only kept in order while its route stays the same; see zoneRoute.
+func zoneRoutePartition(route zoneRoute, numPartitions int32) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(route))
+	return int32(h.Sum32() % uint32(numPartitions))
+}
+
 const (
 	sqlSinkCreateTableStmt = `CREATE TABLE IF NOT EXISTS "%s" (
 		topic STRING,
@@ -165,6 +165,55 @@ func TestKafkaSinkEscaping(t *testing.T) {
 	require.Equal(t, sarama.ByteEncoder(`v☃`), m.Value)
 }
 
+func TestKafkaSinkZoneRouting(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	table := func(name string) *sqlbase.TableDescriptor {
+		return &sqlbase.TableDescriptor{Name: name}
+	}
+
+	ctx := context.Background()
+	p := asyncProducerMock{
+		inputCh:     make(chan *sarama.ProducerMessage, 1),
+		successesCh: make(chan *sarama.ProducerMessage, 1),
+		errorsCh:    make(chan *sarama.ProducerError, 1),
+	}
+	sink := &kafkaSink{
+		producer: p,
+		topics:   map[string]struct{}{`t`: {}},
+	}
+	sink.start()
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	const numPartitions = 16
+	partitioner := newChangefeedPartitioner(`t`)
+	emit := func(key string, route zoneRoute) int32 {
+		require.NoError(t, sink.EmitRoutedRow(ctx, table(`t`), []byte(key), nil, zeroTS, route))
+		m := <-p.inputCh
+		partition, err := partitioner.Partition(m, numPartitions)
+		require.NoError(t, err)
+		return partition
+	}
+
+	// Rows with the same route go to the same partition, whatever their keys.
+	east := emit(`1`, `[+region=east]`)
+	for _, key := range []string{`2`, `3`, `4`} {
+		require.Equal(t, east, emit(key, `[+region=east]`))
+	}
+	require.NotEqual(t, east, emit(`1`, `[+region=west]`))
+
+	// Rows without a route are hashed by key.
+	unrouted, err := partitioner.Partition(&sarama.ProducerMessage{
+		Topic: `t`, Key: sarama.ByteEncoder(`1`),
+	}, numPartitions)
+	require.NoError(t, err)
+	require.Equal(t, unrouted, emit(`1`, ``))
+
+	// The option is only supported by kafka sinks.
+	_, err = getSink(``, 0 /* nodeID */, map[string]string{optZoneRouting: ``}, nil, nil)
+	require.EqualError(t, err, `this sink is incompatible with zone_routing`)
+}
+
 type testEncoder struct{}
 
 func (testEncoder) EncodeKey(encodeRow) ([]byte, error)   { panic(`unimplemented`) }
@@ -0,0 +1,91 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// zoneRoute identifies the zone constraints that a changed row is stored
+// under, rendered by cat.ZoneConstraintSummary. Sinks that support the
+// `zone_routing` option deliver all rows with the same route to the same
+// destination, so that geo-local consumers of a changefeed over a
+// geo-partitioned table can read only their region's data.
+//
+// The destination of a row depends on its zone rather than its key, so when a
+// row moves to another zone, for example because its partition's zone config
+// or its partitioning column changed, its later changes may be delivered to
+// another destination and read before its earlier ones. The usual guarantee
+// that all the changes to a row are delivered in order does not hold, so the
+// option must be given as `zone_routing='unordered'`.
+type zoneRoute string
+
+// zoneRoutingSink is implemented by the sinks that support the `zone_routing`
+// option.
+type zoneRoutingSink interface {
+	Sink
+	// EmitRoutedRow is like EmitRow, but delivers the row to the destination
+	// for the given route instead of the one picked by hashing its key.
+	EmitRoutedRow(
+		ctx context.Context,
+		table *sqlbase.TableDescriptor,
+		key, value []byte,
+		updated hlc.Timestamp,
+		route zoneRoute,
+	) error
+}
+
+// zoneConstraintChangeRouter picks the zoneRoute of each row emitted by a
+// changefeed with the `zone_routing` option from the zone of the index or
+// partition that the row's KV is stored in. It is not threadsafe.
+type zoneConstraintChangeRouter struct {
+	gossip *gossip.Gossip
+
+	// cfg is the SystemConfig the routes were computed from. The zone configs
+	// in a SystemConfig are never modified once it has been gossiped, so routes
+	// are cached by zone until a new one is gossiped.
+	cfg    *config.SystemConfig
+	routes map[*config.ZoneConfig]zoneRoute
+}
+
+func makeZoneConstraintChangeRouter(g *gossip.Gossip) *zoneConstraintChangeRouter {
+	return &zoneConstraintChangeRouter{gossip: g}
+}
+
+// route returns the zoneRoute of the row stored at the given key. Until the
+// SystemConfig has been gossiped, every row gets the empty route, which sinks
+// treat like an unrouted row.
+func (r *zoneConstraintChangeRouter) route(key roachpb.Key) (zoneRoute, error) {
+	cfg := r.gossip.GetSystemConfig()
+	if cfg == nil {
+		return ``, nil
+	}
+	if cfg != r.cfg {
+		r.cfg = cfg
+		r.routes = make(map[*config.ZoneConfig]zoneRoute)
+	}
+
+	zone, err := cfg.GetZoneConfigForKey(roachpb.RKey(key))
+	if err != nil {
+		return ``, err
+	}
+	route, ok := r.routes[zone]
+	if !ok {
+		route = zoneRoute(cat.ZoneConstraintSummary(zone))
+		r.routes[zone] = route
+	}
+	return route, nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/gossipccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -1043,6 +1044,19 @@ func (r *importResumer) Resume(
 	oversample := details.Oversample
 	ingestDirectly := details.IngestDirectly
 
+	tables := make(map[string]*sqlbase.TableDescriptor, len(details.Tables))
+	if details.Tables != nil {
+		for _, i := range details.Tables {
+			if i.Name != "" {
+				tables[i.Name] = i.Desc
+			} else if i.Desc != nil {
+				tables[i.Desc.Name] = i.Desc
+			} else {
+				return errors.Errorf("invalid table specification")
+			}
+		}
+	}
+
 	if sstSize == 0 {
 		// The distributed importer will correctly chunk up large ranges into
 		// multiple ssts that can be imported. In order to reduce the number of
@@ -1053,18 +1067,19 @@ func (r *importResumer) Resume(
 		// size. This should also cause the distsql plan and range router to be much
 		// smaller since there are fewer overall ranges.
 		sstSize = storageccl.MaxImportBatchSize(r.settings) * 5
-	}
 
-	tables := make(map[string]*sqlbase.TableDescriptor, len(details.Tables))
-	if details.Tables != nil {
-		for _, i := range details.Tables {
-			if i.Name != "" {
-				tables[i.Name] = i.Desc
-			} else if i.Desc != nil {
-				tables[i.Desc.Name] = i.Desc
-			} else {
-				return errors.Errorf("invalid table specification")
+		// The target above assumes the default maximum range size. Scale it to the
+		// smallest maximum range size configured for the imported tables, so that
+		// the number of ranges written tracks their zone configs.
+		if err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			rangeMaxBytes, err := minRangeMaxBytes(ctx, txn, tables)
+			if err != nil {
+				return err
 			}
+			sstSize = sstSizeForRangeMaxBytes(sstSize, rangeMaxBytes)
+			return nil
+		}); err != nil {
+			return err
 		}
 	}
 
@@ -1091,6 +1106,45 @@ func (r *importResumer) Resume(
 	return nil
 }
 
+// minRangeMaxBytes returns the smallest range_max_bytes among the zone configs
+// that apply to the given tables, or the default range_max_bytes if there are
+// no tables.
+func minRangeMaxBytes(
+	ctx context.Context, txn *client.Txn, tables map[string]*sqlbase.TableDescriptor,
+) (int64, error) {
+	var res int64
+	for _, desc := range tables {
+		_, zone, _, err := sql.GetZoneConfigInTxn(
+			ctx, txn, uint32(desc.ID), nil /* index */, "" /* partition */, false, /* getInheritedDefault */
+		)
+		if err != nil {
+			return 0, err
+		}
+		if zone.RangeMaxBytes != nil && (res == 0 || *zone.RangeMaxBytes < res) {
+			res = *zone.RangeMaxBytes
+		}
+	}
+	if res == 0 {
+		res = *config.DefaultZoneConfig().RangeMaxBytes
+	}
+	return res, nil
+}
+
+// sstSizeForRangeMaxBytes scales the given target SST size, which is chosen
+// for the default range_max_bytes, by the ratio of rangeMaxBytes to the
+// default. The result is capped so that it can still be sampled.
+func sstSizeForRangeMaxBytes(sstSize int64, rangeMaxBytes int64) int64 {
+	defaultRangeMaxBytes := *config.DefaultZoneConfig().RangeMaxBytes
+	if rangeMaxBytes <= 0 || rangeMaxBytes == defaultRangeMaxBytes {
+		return sstSize
+	}
+	scaled := float64(sstSize) * float64(rangeMaxBytes) / float64(defaultRangeMaxBytes)
+	if scaled > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int64(scaled)
+}
+
 // OnFailOrCancel removes KV data that has been committed from a import that
 // has failed or been canceled. It does this by adding the table descriptors
 // in DROP state, which causes the schema change stuff to delete the keys
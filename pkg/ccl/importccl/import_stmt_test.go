@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -27,6 +28,8 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -2466,3 +2469,67 @@ func TestCreateStatsAfterImport(t *testing.T) {
 			{"__auto__", "{i}", "1", "1", "0"},
 		})
 }
+
+func TestSSTSizeForRangeMaxBytes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const sstSize = 160 << 20
+	defaultRangeMaxBytes := *config.DefaultZoneConfig().RangeMaxBytes
+
+	for _, tc := range []struct {
+		rangeMaxBytes int64
+		expected      int64
+	}{
+		{rangeMaxBytes: 0, expected: sstSize},
+		{rangeMaxBytes: defaultRangeMaxBytes, expected: sstSize},
+		{rangeMaxBytes: defaultRangeMaxBytes / 4, expected: sstSize / 4},
+		{rangeMaxBytes: defaultRangeMaxBytes * 2, expected: sstSize * 2},
+		{rangeMaxBytes: defaultRangeMaxBytes * 64, expected: math.MaxInt32},
+	} {
+		if actual := sstSizeForRangeMaxBytes(sstSize, tc.rangeMaxBytes); actual != tc.expected {
+			t.Errorf("range_max_bytes %d: expected SST size %d, got %d",
+				tc.rangeMaxBytes, tc.expected, actual)
+		}
+	}
+}
+
+func TestImportMinRangeMaxBytes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.a (k INT PRIMARY KEY)`)
+	sqlDB.Exec(t, `CREATE TABLE d.b (k INT PRIMARY KEY)`)
+
+	tables := map[string]*sqlbase.TableDescriptor{
+		"a": sqlbase.GetTableDescriptor(kvDB, "d", "a"),
+		"b": sqlbase.GetTableDescriptor(kvDB, "d", "b"),
+	}
+	check := func(expected int64) {
+		t.Helper()
+		if err := kvDB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			actual, err := minRangeMaxBytes(ctx, txn, tables)
+			if err != nil {
+				return err
+			}
+			if actual != expected {
+				return errors.Errorf("expected range_max_bytes %d, got %d", expected, actual)
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check(*config.DefaultZoneConfig().RangeMaxBytes)
+
+	sqlDB.Exec(t, `ALTER TABLE d.b CONFIGURE ZONE USING range_min_bytes = 1 << 20, range_max_bytes = 16 << 20`)
+	check(16 << 20)
+
+	sqlDB.Exec(t, `ALTER DATABASE d CONFIGURE ZONE USING range_min_bytes = 1 << 20, range_max_bytes = 8 << 20`)
+	check(8 << 20)
+}
@@ -189,6 +189,7 @@ type basicMetadata struct {
 	kind       string // "string", "table", or "view"
 	alter      []string
 	validate   []string
+	zones      []string
 }
 
 // tableMetadata describes one table to dump.
@@ -348,6 +349,12 @@ func getBasicMetadata(conn *sqlConn, dbName, tableName string, ts string) (basic
 		return basicMetadata{}, err
 	}
 
+	// Get zone configs.
+	zones, err := getZoneConfigStatements(conn, name, id, ts)
+	if err != nil {
+		return basicMetadata{}, err
+	}
+
 	md := basicMetadata{
 		ID:         id,
 		name:       tree.NewTableName(tree.Name(dbName), tree.Name(tableName)),
@@ -356,11 +363,96 @@ func getBasicMetadata(conn *sqlConn, dbName, tableName string, ts string) (basic
 		kind:       kind,
 		alter:      alterStatements,
 		validate:   validateStatements,
+		zones:      zones,
 	}
 
 	return md, nil
 }
 
+// getZoneConfigStatements retrieves the ALTER ... CONFIGURE ZONE statements
+// for the zone config of the specified table and the zone configs of its
+// indexes and partitions.
+func getZoneConfigStatements(
+	conn *sqlConn, name *tree.TableName, id int64, ts string,
+) ([]string, error) {
+	rows, err := conn.Query(fmt.Sprintf(`
+		SELECT config_sql
+		FROM %s.crdb_internal.zones
+		AS OF SYSTEM TIME %s
+		WHERE zone_id = $1
+			AND config_sql IS NOT NULL
+		`, &name.CatalogName, lex.EscapeSQLString(ts)), []driver.Value{id})
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]driver.Value, 1)
+
+	var configSQL []string
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		sqlI := vals[0]
+		sql, ok := sqlI.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected value: %T", sqlI)
+		}
+		configSQL = append(configSQL, sql)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	return makeZoneConfigStatements(name, configSQL)
+}
+
+// makeZoneConfigStatements rewrites the ALTER ... CONFIGURE ZONE statements
+// retrieved from crdb_internal.zones so that they refer to the table by its
+// unqualified name, like the CREATE statement does. The statements are
+// ordered such that the zone of the table is configured before the zones of
+// its indexes, which are in turn configured before the zones of their
+// partitions.
+func makeZoneConfigStatements(name *tree.TableName, configSQL []string) ([]string, error) {
+	zones := make([]*tree.SetZoneConfig, 0, len(configSQL))
+	for _, sql := range configSQL {
+		// A zone that inherits all of its fields has nothing to configure.
+		if strings.HasSuffix(strings.TrimSpace(sql), "CONFIGURE ZONE USING") {
+			continue
+		}
+		stmt, err := parser.ParseOne(sql)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse zone config %q", sql)
+		}
+		zone, ok := stmt.AST.(*tree.SetZoneConfig)
+		if !ok || !zone.TargetsTable() {
+			return nil, fmt.Errorf("unexpected zone config statement: %s", sql)
+		}
+		zone.TableOrIndex.Table = tree.MakeUnqualifiedTableName(name.TableName)
+		zones = append(zones, zone)
+	}
+
+	depth := func(zs *tree.ZoneSpecifier) int {
+		if zs.Partition != "" {
+			return 2
+		}
+		if zs.TargetsIndex() {
+			return 1
+		}
+		return 0
+	}
+	sort.SliceStable(zones, func(i, j int) bool {
+		return depth(&zones[i].ZoneSpecifier) < depth(&zones[j].ZoneSpecifier)
+	})
+
+	stmts := make([]string, len(zones))
+	for i, zone := range zones {
+		stmts[i] = tree.AsStringWithFlags(zone, tree.FmtParsable)
+	}
+	return stmts, nil
+}
+
 func extractArray(val interface{}) ([]string, error) {
 	b, ok := val.([]byte)
 	if !ok {
@@ -470,7 +562,8 @@ func getMetadataForTable(conn *sqlConn, md basicMetadata, ts string) (tableMetad
 	}, nil
 }
 
-// dumpCreateTable dumps the CREATE statement of the specified table to w.
+// dumpCreateTable dumps the CREATE statement of the specified table to w,
+// followed by the statements that configure its zones.
 func dumpCreateTable(w io.Writer, md basicMetadata) error {
 	if _, err := w.Write([]byte(md.createStmt)); err != nil {
 		return err
@@ -478,6 +571,11 @@ func dumpCreateTable(w io.Writer, md basicMetadata) error {
 	if _, err := w.Write([]byte(";\n")); err != nil {
 		return err
 	}
+	for _, zone := range md.zones {
+		if _, err := fmt.Fprintf(w, "%s;\n", zone); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
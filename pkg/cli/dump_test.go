@@ -29,6 +29,7 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/testutils/datadriven"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
@@ -166,6 +167,35 @@ func TestDumpBytes(t *testing.T) {
 	}
 }
 
+// TestDumpWithZoneConfigs verifies that the zone config statements retrieved
+// from crdb_internal.zones are rewritten to use the unqualified table name and
+// ordered so that table zones precede index zones, which precede partition
+// zones. The end-to-end behavior is covered by testdata/dump/zone_config.
+func TestDumpWithZoneConfigs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	name := tree.NewTableName("d", "t")
+	configSQL := []string{
+		"ALTER PARTITION p OF TABLE d.public.t CONFIGURE ZONE USING\n\tnum_replicas = 3",
+		"ALTER INDEX d.public.t@idx CONFIGURE ZONE USING\n\tconstraints = '[+region=east]'",
+		"ALTER TABLE d.public.t CONFIGURE ZONE USING\n\trange_min_bytes = 1024,\n\tgc.ttlseconds = 600",
+		"ALTER INDEX d.public.t@primary CONFIGURE ZONE USING\n",
+	}
+	expected := []string{
+		`ALTER TABLE t CONFIGURE ZONE USING range_min_bytes = 1024, "gc.ttlseconds" = 600`,
+		`ALTER INDEX t@idx CONFIGURE ZONE USING constraints = '[+region=east]'`,
+		`ALTER PARTITION p OF TABLE t CONFIGURE ZONE USING num_replicas = 3`,
+	}
+
+	stmts, err := makeZoneConfigStatements(name, configSQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stmts, expected) {
+		t.Fatalf("expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(stmts, "\n"))
+	}
+}
+
 const durationRandom = "duration-random"
 
 var randomTestTime = pflag.Duration(durationRandom, time.Second, "duration for randomized dump test to run")
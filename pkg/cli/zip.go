@@ -25,6 +25,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -39,8 +40,8 @@ var debugZipCmd = &cobra.Command{
 	Long: `
 
 Gather cluster debug data into a zip file. Data includes cluster events, node
-liveness, node status, range status, node stack traces, log files, and SQL
-schema.
+liveness, node status, range status, zone placement, node stack traces, log
+files, and SQL schema.
 
 Retrieval of per-node details (status, stack traces, range status) requires the
 node to be live and operating properly. Retrieval of SQL data requires the
@@ -259,6 +260,9 @@ func runDebugZip(cmd *cobra.Command, args []string) error {
 				return err
 			}
 		} else {
+			// The descriptors of the ranges on every node are collected for
+			// the zone placement report.
+			rangeDescs := make(map[roachpb.RangeID]roachpb.RangeDescriptor)
 			for _, node := range nodes.Nodes {
 				id := fmt.Sprintf("%d", node.Desc.NodeID)
 				prefix := fmt.Sprintf("%s/%s", nodesPrefix, id)
@@ -393,6 +397,7 @@ func runDebugZip(cmd *cobra.Command, args []string) error {
 							ranges.Ranges[j].State.Desc.RangeID
 					})
 					for _, r := range ranges.Ranges {
+						rangeDescs[r.State.Desc.RangeID] = *r.State.Desc
 						name := fmt.Sprintf("%s/ranges/%s", prefix, r.State.Desc.RangeID)
 						if err := z.createJSON(name, r); err != nil {
 							return err
@@ -400,6 +405,12 @@ func runDebugZip(cmd *cobra.Command, args []string) error {
 					}
 				}
 			}
+
+			if err := zipZonePlacementReport(
+				baseCtx, z, reportsPrefix+"/zone_placement_report.json", status, timeout, nodes.Nodes, rangeDescs,
+			); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -0,0 +1,158 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License. See the AUTHORS file
+// for names of contributors.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/status/statuspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
+	"github.com/pkg/errors"
+)
+
+// zonePlacement describes how well the replicas of the ranges that share a
+// zone are placed, for the zone placement report in debug zip.
+type zonePlacement struct {
+	// Zone is the zone of the ranges, formatted by cat.ZoneToJSON.
+	Zone json.RawMessage `json:"zone"`
+	// SampleStartKey is the start key of the first of the ranges, which
+	// identifies the object that the zone applies to.
+	SampleStartKey string `json:"sample_start_key"`
+	Ranges         int    `json:"ranges"`
+	Replicas       int    `json:"replicas"`
+	// The locality scores are the cat.LocalityScore of the zone for the
+	// localities of the nodes holding the replicas. They are omitted if the
+	// zone has no replica constraints or if no node locality is known.
+	MinLocalityScore  *float64 `json:"min_locality_score,omitempty"`
+	MeanLocalityScore *float64 `json:"mean_locality_score,omitempty"`
+}
+
+// zipZonePlacementReport writes the zone placement report for the given ranges
+// to the zip. The zones of the ranges are resolved from the SystemConfig
+// gossiped to the node that the command is connected to.
+func zipZonePlacementReport(
+	ctx context.Context,
+	z *zipper,
+	name string,
+	status serverpb.StatusClient,
+	timeout time.Duration,
+	nodes []statuspb.NodeStatus,
+	descs map[roachpb.RangeID]roachpb.RangeDescriptor,
+) error {
+	var report []zonePlacement
+	err := contextutil.RunWithTimeout(ctx, "request system config", timeout, func(ctx context.Context) error {
+		infoStatus, err := status.Gossip(ctx, &serverpb.GossipRequest{NodeId: "local"})
+		if err != nil {
+			return err
+		}
+		info, ok := infoStatus.Infos[gossip.KeySystemConfig]
+		if !ok {
+			return errors.New("system config not yet gossiped")
+		}
+		cfg := config.NewSystemConfig()
+		if err := info.Value.GetProto(&cfg.SystemConfigEntries); err != nil {
+			return err
+		}
+		report, err = makeZonePlacementReport(cfg, nodes, descs)
+		return err
+	})
+	return z.createJSONOrError(name, report, err)
+}
+
+// makeZonePlacementReport groups the given ranges by zone, and scores the
+// placement of their replicas against the constraints of the zone. Zones are
+// resolved for each table or other object separately, so the ranges of
+// objects that inherit the same zone are reported separately too. Zones are
+// ordered by the start key of their first range.
+func makeZonePlacementReport(
+	cfg *config.SystemConfig,
+	nodes []statuspb.NodeStatus,
+	descs map[roachpb.RangeID]roachpb.RangeDescriptor,
+) ([]zonePlacement, error) {
+	localities := make(map[roachpb.NodeID]roachpb.Locality, len(nodes))
+	for i := range nodes {
+		localities[nodes[i].Desc.NodeID] = nodes[i].Desc.Locality
+	}
+
+	sorted := make([]roachpb.RangeDescriptor, 0, len(descs))
+	for _, desc := range descs {
+		sorted = append(sorted, desc)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartKey.Less(sorted[j].StartKey)
+	})
+
+	type zoneScores struct {
+		placement zonePlacement
+		min, sum  float64
+		scored    int
+	}
+	var zones []*zoneScores
+	byZone := make(map[*config.ZoneConfig]*zoneScores)
+	for i := range sorted {
+		desc := &sorted[i]
+		zone, err := cfg.GetZoneConfigForKey(desc.StartKey)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := byZone[zone]
+		if !ok {
+			s = &zoneScores{
+				placement: zonePlacement{
+					Zone:           json.RawMessage(cat.ZoneToJSON(zone).String()),
+					SampleStartKey: desc.StartKey.String(),
+				},
+				min: math.Inf(1),
+			}
+			byZone[zone] = s
+			zones = append(zones, s)
+		}
+		s.placement.Ranges++
+		s.placement.Replicas += len(desc.Replicas)
+		if zone.ReplicaConstraintsCount() == 0 {
+			continue
+		}
+		for _, replica := range desc.Replicas {
+			locality, ok := localities[replica.NodeID]
+			if !ok || len(locality.Tiers) == 0 {
+				continue
+			}
+			score := cat.LocalityScore(zone, locality)
+			s.min = math.Min(s.min, score)
+			s.sum += score
+			s.scored++
+		}
+	}
+
+	report := make([]zonePlacement, len(zones))
+	for i, s := range zones {
+		report[i] = s.placement
+		if s.scored != 0 {
+			mean := s.sum / float64(s.scored)
+			report[i].MinLocalityScore = &s.min
+			report[i].MeanLocalityScore = &mean
+		}
+	}
+	return report, nil
+}
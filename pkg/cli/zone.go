@@ -18,11 +18,14 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/config/zonelint"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/version"
@@ -312,11 +315,107 @@ func runSetZone(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// A lintZonesCmd command reports potential problems with the zone configs.
+var lintZonesCmd = &cobra.Command{
+	Use:   "lint [options]",
+	Short: "report potential problems with zone configs",
+	Long: `
+Check every zone config for potential problems and report them, one per line:
+
+  - replica constraints that cannot be satisfied by the localities of the
+    nodes currently in the cluster
+  - table zones that repeat the zone of their database unchanged
+  - table, index, and partition zones whose constraints contradict the
+    constraints of another zone in the same database
+  - zones with range_max_bytes smaller than range_min_bytes
+
+Exits with a non-zero code if any problems are found.
+`,
+	Args: cobra.NoArgs,
+	RunE: MaybeDecorateGRPCError(runLintZones),
+}
+
+func runLintZones(cmd *cobra.Command, args []string) error {
+	conn, err := getPasswordAndMakeSQLClient("cockroach zone")
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	zones, err := getLintZones(conn)
+	if err != nil {
+		return err
+	}
+	localities, err := getNodeLocalities(conn)
+	if err != nil {
+		return err
+	}
+
+	issues := zonelint.Lint(zones, localities)
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("found %d zone config issue(s)", len(issues))
+	}
+	return nil
+}
+
+func getLintZones(conn *sqlConn) ([]zonelint.Zone, error) {
+	rows, err := conn.Query(zonelint.ZonesQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var zones []zonelint.Zone
+	vals := make([]driver.Value, 2)
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		z, err := zonelint.MakeZone(vals[0].(string), vals[1].([]byte))
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, z)
+	}
+	return zones, nil
+}
+
+// getNodeLocalities returns the locality of every node in the cluster.
+func getNodeLocalities(conn *sqlConn) ([]roachpb.Locality, error) {
+	rows, err := conn.Query(zonelint.LocalitiesQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var localities []roachpb.Locality
+	vals := make([]driver.Value, 1)
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		locality, err := zonelint.ParseLocality(vals[0].(string))
+		if err != nil {
+			return nil, err
+		}
+		localities = append(localities, locality)
+	}
+	return localities, nil
+}
+
 var zoneCmds = []*cobra.Command{
 	getZoneCmd,
 	lsZonesCmd,
 	rmZoneCmd,
 	setZoneCmd,
+	lintZonesCmd,
 }
 
 var zoneCmd = &cobra.Command{
@@ -1158,7 +1158,7 @@ var specs = []stmtSpec{
 	},
 	{
 		name:   "show_zone_stmt",
-		inline: []string{"opt_partition", "table_index_name", "partition"},
+		inline: []string{"zone_specifier", "opt_partition", "table_index_name", "partition"},
 	},
 	{
 		name:   "sort_clause",
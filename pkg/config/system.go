@@ -279,6 +279,45 @@ func (s *SystemConfig) GetZoneConfigForObject(id uint32) (*ZoneConfig, error) {
 	return entry.zone, nil
 }
 
+// GetSubzoneBoundariesForObject returns the sorted keys at which the zone
+// config of the given object changes, which are the start and end keys of the
+// spans of its subzones, such as the partitions of its indexes.
+func (s *SystemConfig) GetSubzoneBoundariesForObject(id uint32) ([]roachpb.Key, error) {
+	entry, err := s.getZoneEntry(id)
+	if err != nil {
+		return nil, err
+	}
+	zone := entry.placeholder
+	if zone == nil {
+		zone = entry.zone
+	}
+	if zone == nil || len(zone.SubzoneSpans) == 0 {
+		return nil, nil
+	}
+
+	prefix := roachpb.Key(keys.MakeTablePrefix(id))
+	boundaries := make([]roachpb.Key, 0, 2*len(zone.SubzoneSpans))
+	for _, span := range zone.SubzoneSpans {
+		key := append(prefix[:len(prefix):len(prefix)], span.Key...)
+		endKey := key.PrefixEnd()
+		if len(span.EndKey) != 0 {
+			endKey = append(prefix[:len(prefix):len(prefix)], span.EndKey...)
+		}
+		boundaries = append(boundaries, key, endKey)
+	}
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].Compare(boundaries[j]) < 0
+	})
+	// Remove duplicates, since adjacent subzone spans share a boundary.
+	deduped := boundaries[:1]
+	for _, key := range boundaries[1:] {
+		if !key.Equal(deduped[len(deduped)-1]) {
+			deduped = append(deduped, key)
+		}
+	}
+	return deduped, nil
+}
+
 func (s *SystemConfig) getZoneEntry(id uint32) (zoneEntry, error) {
 	s.mu.RLock()
 	entry, ok := s.mu.zoneCache[id]
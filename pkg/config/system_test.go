@@ -15,6 +15,7 @@
 package config_test
 
 import (
+	"reflect"
 	"sort"
 	"testing"
 
@@ -443,3 +444,48 @@ func TestGetZoneConfigForKey(t *testing.T) {
 		}
 	}
 }
+
+func TestGetSubzoneBoundariesForObject(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	originalZoneConfigHook := config.ZoneConfigHook
+	defer func() {
+		config.ZoneConfigHook = originalZoneConfigHook
+	}()
+
+	const id = keys.MinUserDescID
+	prefix := keys.MakeTablePrefix(id)
+	key := func(suffix string) roachpb.Key {
+		return roachpb.Key(append(append([]byte(nil), prefix...), suffix...))
+	}
+	zone := &config.ZoneConfig{SubzoneSpans: []config.SubzoneSpan{
+		{Key: roachpb.Key("b"), EndKey: roachpb.Key("d")},
+		{Key: roachpb.Key("d"), EndKey: roachpb.Key("f")},
+		{Key: roachpb.Key("a")},
+	}}
+
+	testCases := []struct {
+		zone, placeholder *config.ZoneConfig
+		expected          []roachpb.Key
+	}{
+		{nil, nil, nil},
+		{&config.ZoneConfig{}, nil, nil},
+		{zone, nil, []roachpb.Key{key("a"), key("b"), key("d"), key("f")}},
+		{&config.ZoneConfig{}, zone, []roachpb.Key{key("a"), key("b"), key("d"), key("f")}},
+	}
+
+	for i, tc := range testCases {
+		config.ZoneConfigHook = func(
+			_ *config.SystemConfig, _ uint32,
+		) (*config.ZoneConfig, *config.ZoneConfig, bool, error) {
+			return tc.zone, tc.placeholder, false, nil
+		}
+		boundaries, err := config.NewSystemConfig().GetSubzoneBoundariesForObject(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(boundaries, tc.expected) {
+			t.Errorf("%d: expected %v, got %v", i, tc.expected, boundaries)
+		}
+	}
+}
@@ -728,6 +728,14 @@ func (z ZoneConfig) subzoneSplits() []roachpb.RKey {
 	return out
 }
 
+// ReplicaCount is part of the cat.Zone interface.
+func (z *ZoneConfig) ReplicaCount() int32 {
+	if z.NumReplicas == nil {
+		return 0
+	}
+	return *z.NumReplicas
+}
+
 // ReplicaConstraintsCount is part of the cat.Zone interface.
 func (z *ZoneConfig) ReplicaConstraintsCount() int {
 	return len(z.Constraints)
@@ -738,26 +746,56 @@ func (z *ZoneConfig) ReplicaConstraints(i int) cat.ReplicaConstraints {
 	return &z.Constraints[i]
 }
 
+// LeasePreferenceCount is part of the cat.Zone interface.
+func (z *ZoneConfig) LeasePreferenceCount() int {
+	return len(z.LeasePreferences)
+}
+
+// LeasePreference is part of the cat.Zone interface.
+func (z *ZoneConfig) LeasePreference(i int) cat.ConstraintSet {
+	return &z.LeasePreferences[i]
+}
+
+// ConstraintString is part of the cat.Zone interface.
+func (z *ZoneConfig) ConstraintString() string {
+	return cat.FormatZoneConstraints(z)
+}
+
 // ReplicaCount is part of the cat.ReplicaConstraints interface.
 func (c *Constraints) ReplicaCount() int32 {
 	return c.NumReplicas
 }
 
-// ConstraintCount is part of the cat.ReplicaConstraints interface.
+// ConstraintCount is part of the cat.ConstraintSet interface.
 func (c *Constraints) ConstraintCount() int {
 	return len(c.Constraints)
 }
 
-// Constraint is part of the cat.ReplicaConstraints interface.
+// Constraint is part of the cat.ConstraintSet interface.
 func (c *Constraints) Constraint(i int) cat.Constraint {
 	return &c.Constraints[i]
 }
 
+// ConstraintCount is part of the cat.ConstraintSet interface.
+func (l *LeasePreference) ConstraintCount() int {
+	return len(l.Constraints)
+}
+
+// Constraint is part of the cat.ConstraintSet interface.
+func (l *LeasePreference) Constraint(i int) cat.Constraint {
+	return &l.Constraints[i]
+}
+
 // IsRequired is part of the cat.Constraint interface.
 func (c *Constraint) IsRequired() bool {
 	return c.Type == Constraint_REQUIRED
 }
 
+// IsProhibited is part of the cat.Constraint interface.
+func (c *Constraint) IsProhibited() bool {
+	return c.Type == Constraint_PROHIBITED
+}
+
 // GetKey is part of the cat.Constraint interface.
 func (c *Constraint) GetKey() string {
 	return c.Key
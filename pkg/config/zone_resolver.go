@@ -0,0 +1,104 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+// ZoneInheritanceResolver computes the effective zone configs of tables,
+// indexes and partitions from a SystemConfig. The effective zone of each level
+// of the hierarchy (default → database → table → index → partition) sets the
+// fields that are set at that level and inherits the rest from the level
+// above.
+//
+// The effective zones are cached, so that the zones of an index and of each
+// of its partitions are only merged once. Since the SystemConfig is never
+// modified once gossiped, a resolver is only valid for the SystemConfig it was
+// created with. A ZoneInheritanceResolver is not safe for concurrent use.
+type ZoneInheritanceResolver struct {
+	cfg   *SystemConfig
+	zones map[resolvedZoneKey]*ZoneConfig
+}
+
+// resolvedZoneKey identifies a level of the zone hierarchy below the database.
+// An indexID of 0 is the table itself, and an empty partition is the whole
+// index.
+type resolvedZoneKey struct {
+	tableID   uint32
+	indexID   uint32
+	partition string
+}
+
+// NewZoneInheritanceResolver creates a resolver for the zones in cfg.
+func NewZoneInheritanceResolver(cfg *SystemConfig) *ZoneInheritanceResolver {
+	return &ZoneInheritanceResolver{cfg: cfg, zones: make(map[resolvedZoneKey]*ZoneConfig)}
+}
+
+// ResolveEffectiveZone returns the effective zone config of the given
+// partition of the given index of the table with ID tableID. An indexID of 0
+// returns the effective zone of the table, and an empty partitionName returns
+// the effective zone of the whole index.
+//
+// The effective zone of a table includes the subzones of the table, even if
+// the table's own zone config only exists to hold them. The returned zone
+// config must not be modified. It is nil if no zone config applies to the
+// table, which only happens when ZoneConfigHook is overridden.
+func (r *ZoneInheritanceResolver) ResolveEffectiveZone(
+	tableID, indexID uint32, partitionName string,
+) (*ZoneConfig, error) {
+	key := resolvedZoneKey{tableID: tableID, indexID: indexID, partition: partitionName}
+	if zone, ok := r.zones[key]; ok {
+		return zone, nil
+	}
+
+	var zone *ZoneConfig
+	if indexID == 0 {
+		entry, err := r.cfg.getZoneEntry(tableID)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case entry.zone == nil:
+			// This can happen with tests that override ZoneConfigHook.
+		case entry.placeholder != nil:
+			// The table's zone config only holds subzones, so the zone that
+			// applies to the table is its parent's.
+			withSubzones := *entry.zone
+			withSubzones.Subzones = entry.placeholder.Subzones
+			withSubzones.SubzoneSpans = entry.placeholder.SubzoneSpans
+			zone = &withSubzones
+		default:
+			zone = entry.zone
+		}
+	} else {
+		table, err := r.ResolveEffectiveZone(tableID, 0, "")
+		if err != nil || table == nil {
+			return nil, err
+		}
+		parent := table
+		if partitionName != "" {
+			if parent, err = r.ResolveEffectiveZone(tableID, indexID, ""); err != nil {
+				return nil, err
+			}
+		}
+
+		zone = parent
+		if subzone := table.GetSubzone(indexID, partitionName); subzone != nil {
+			merged := subzone.Config
+			merged.InheritFromParent(*parent)
+			zone = &merged
+		}
+	}
+
+	r.zones[key] = zone
+	return zone, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestZoneInheritanceResolver(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	originalZoneConfigHook := config.ZoneConfigHook
+	defer func() {
+		config.ZoneConfigHook = originalZoneConfigHook
+	}()
+
+	inRegion := func(region string) []config.Constraints {
+		return []config.Constraints{{Constraints: []config.Constraint{
+			{Type: config.Constraint_REQUIRED, Key: "region", Value: region},
+		}}}
+	}
+
+	// The table only has a placeholder zone config for its subzones, so the
+	// table inherits the zone of its database.
+	const id = keys.MinUserDescID
+	dbZone := &config.ZoneConfig{
+		NumReplicas: proto.Int32(3),
+		GC:          &config.GCPolicy{TTLSeconds: 100},
+		Constraints: inRegion("east"),
+	}
+	placeholder := &config.ZoneConfig{
+		NumReplicas: proto.Int32(0),
+		Subzones: []config.Subzone{
+			{IndexID: 1, Config: config.ZoneConfig{
+				NumReplicas:          proto.Int32(5),
+				InheritedConstraints: true,
+			}},
+			{IndexID: 1, PartitionName: "p", Config: config.ZoneConfig{
+				Constraints: inRegion("west"),
+			}},
+		},
+	}
+	hookCalls := 0
+	config.ZoneConfigHook = func(
+		_ *config.SystemConfig, _ uint32,
+	) (*config.ZoneConfig, *config.ZoneConfig, bool, error) {
+		hookCalls++
+		return dbZone, placeholder, false, nil
+	}
+
+	resolver := config.NewZoneInheritanceResolver(config.NewSystemConfig())
+	resolve := func(indexID uint32, partition string) *config.ZoneConfig {
+		zone, err := resolver.ResolveEffectiveZone(id, indexID, partition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return zone
+	}
+
+	table := resolve(0, "")
+	if *table.NumReplicas != 3 || len(table.Subzones) != 2 {
+		t.Errorf("expected the database zone with the table's subzones, got %+v", table)
+	}
+
+	index := resolve(1, "")
+	if *index.NumReplicas != 5 || index.GC.TTLSeconds != 100 ||
+		!reflect.DeepEqual(index.Constraints, inRegion("east")) {
+		t.Errorf("expected index zone to inherit from the table zone, got %+v", index)
+	}
+
+	partition := resolve(1, "p")
+	if *partition.NumReplicas != 5 || !reflect.DeepEqual(partition.Constraints, inRegion("west")) {
+		t.Errorf("expected partition zone to inherit from the index zone, got %+v", partition)
+	}
+
+	if other := resolve(2, ""); other != table {
+		t.Errorf("expected index without a subzone to use the table zone, got %+v", other)
+	}
+	if again := resolve(1, "p"); again != partition {
+		t.Errorf("expected cached partition zone, got %+v", again)
+	}
+	if hookCalls != 1 {
+		t.Errorf("expected the zone entry to be looked up once, got %d lookups", hookCalls)
+	}
+	if placeholder.Subzones[1].Config.NumReplicas != nil || placeholder.Subzones[1].Config.GC != nil {
+		t.Errorf("expected the placeholder's subzones not to be modified, got %+v", placeholder.Subzones[1])
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package zonelint checks the zone configs of a cluster for potential
+// problems. It is used by `cockroach zone lint` and by `workload run`, which
+// read the zones and node localities with ZonesQuery and LocalitiesQuery
+// through their own SQL clients.
+package zonelint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+// ZonesQuery lists the name and config protobuf of every zone to lint. Each
+// row can be passed to MakeZone.
+const ZonesQuery = `
+SELECT zone_name, config_protobuf
+FROM crdb_internal.zones
+WHERE zone_name IS NOT NULL
+ORDER BY zone_id`
+
+// LocalitiesQuery lists the locality of every node in the cluster as JSON.
+// Each row can be passed to ParseLocality.
+const LocalitiesQuery = `SELECT locality::STRING FROM crdb_internal.gossip_nodes`
+
+// Zone is a zone config as listed by crdb_internal.zones. Index and partition
+// zones do not include the fields they inherit from their table.
+type Zone struct {
+	Name      string
+	Specifier tree.ZoneSpecifier
+	Config    config.ZoneConfig
+}
+
+// MakeZone returns the Zone with the given name and config protobuf.
+func MakeZone(name string, configProto []byte) (Zone, error) {
+	z := Zone{Name: name}
+	var err error
+	if z.Specifier, err = config.ParseCLIZoneSpecifier(name); err != nil {
+		return Zone{}, err
+	}
+	if err := protoutil.Unmarshal(configProto, &z.Config); err != nil {
+		return Zone{}, err
+	}
+	return z, nil
+}
+
+// ParseLocality parses a locality as returned by LocalitiesQuery.
+func ParseLocality(localityJSON string) (roachpb.Locality, error) {
+	var tiers map[string]string
+	if err := json.Unmarshal([]byte(localityJSON), &tiers); err != nil {
+		return roachpb.Locality{}, err
+	}
+	var locality roachpb.Locality
+	for key, value := range tiers {
+		locality.Tiers = append(locality.Tiers, roachpb.Tier{Key: key, Value: value})
+	}
+	return locality, nil
+}
+
+// Lint returns a description of each potential problem found in the zones,
+// given the localities of the nodes in the cluster. If no localities are given,
+// constraints are not checked for satisfiability.
+func Lint(zones []Zone, localities []roachpb.Locality) []string {
+	zonesByName := make(map[string]*Zone, len(zones))
+	for i := range zones {
+		zonesByName[zones[i].Name] = &zones[i]
+	}
+
+	// Find constraints that conflict with the constraints of another table,
+	// index, or partition zone in the same database.
+	conflicts := make(map[int][]cat.ZoneConstraintConflict)
+	var dbNames []string
+	dbZones := make(map[string][]int)
+	for i := range zones {
+		if zones[i].Specifier.TableOrIndex.Table.TableName == "" {
+			continue
+		}
+		dbName := zones[i].Specifier.TableOrIndex.Table.Catalog()
+		if _, ok := dbZones[dbName]; !ok {
+			dbNames = append(dbNames, dbName)
+		}
+		dbZones[dbName] = append(dbZones[dbName], i)
+	}
+	var detector cat.ZoneConstraintConflictDetector
+	for _, dbName := range dbNames {
+		detector.Reset()
+		for _, i := range dbZones[dbName] {
+			detector.Add(&zones[i].Config)
+		}
+		for _, c := range detector.Conflicts() {
+			// Map the indexes of the zones back into the zones slice.
+			c.RequiredZone = dbZones[dbName][c.RequiredZone]
+			c.ProhibitedZone = dbZones[dbName][c.ProhibitedZone]
+			conflicts[c.RequiredZone] = append(conflicts[c.RequiredZone], c)
+		}
+	}
+
+	nodes := make([]roachpb.NodeDescriptor, len(localities))
+	for i := range localities {
+		nodes[i].Locality = localities[i]
+	}
+
+	var issues []string
+	for i := range zones {
+		z := &zones[i]
+		report := func(format string, args ...interface{}) {
+			issues = append(issues, z.Name+": "+fmt.Sprintf(format, args...))
+		}
+
+		if err := cat.ValidateZone(&z.Config); err != nil {
+			report("%v", err)
+		}
+		if len(localities) > 0 {
+			unsatisfied := false
+			for j, n := 0, z.Config.ReplicaConstraintsCount(); j < n; j++ {
+				replConstraint := z.Config.ReplicaConstraints(j)
+				required := int(replConstraint.ReplicaCount())
+				if required == 0 {
+					required = 1
+				}
+				matching := 0
+				for _, locality := range localities {
+					if cat.LocalitySatisfiesConstraints(locality, replConstraint) {
+						matching++
+					}
+				}
+				if matching < required {
+					report("constraints %s require %d node(s) but only %d node(s) match",
+						formatConstraints(replConstraint), required, matching)
+					unsatisfied = true
+				}
+			}
+			if unsatisfied {
+				if suggestions := cat.SuggestNodesToSatisfyZone(&z.Config, nodes); len(suggestions) > 0 {
+					report("adding nodes with localities %s would satisfy the constraints",
+						strings.Join(suggestions, "; "))
+				}
+			}
+		}
+
+		if z.Specifier.TableOrIndex.Table.TableName != "" &&
+			z.Specifier.TableOrIndex.Index == "" && z.Specifier.Partition == "" {
+			// This is a table zone.
+			if dbZone, ok := zonesByName[z.Specifier.TableOrIndex.Table.Catalog()]; ok &&
+				z.Config.Equal(&dbZone.Config) {
+				report("zone is identical to the zone of database %s", dbZone.Name)
+			}
+		}
+		for _, c := range conflicts[i] {
+			report("constraint %s contradicts the constraints of %s", c.Required, zones[c.ProhibitedZone].Name)
+		}
+
+		if z.Config.RangeMinBytes != nil && z.Config.RangeMaxBytes != nil &&
+			*z.Config.RangeMaxBytes < *z.Config.RangeMinBytes {
+			report("range_max_bytes %d is less than range_min_bytes %d",
+				*z.Config.RangeMaxBytes, *z.Config.RangeMinBytes)
+		}
+	}
+	return issues
+}
+
+// formatConstraints formats a constraint set like [+region=east,-dc=1].
+func formatConstraints(set cat.ConstraintSet) string {
+	parts := make([]string, set.ConstraintCount())
+	for i := range parts {
+		parts[i] = fmt.Sprint(set.Constraint(i))
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package zonelint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestLint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+	makeZone := func(name string, zone config.ZoneConfig) Zone {
+		zs, err := config.ParseCLIZoneSpecifier(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return Zone{Name: name, Specifier: zs, Config: zone}
+	}
+
+	dbZone := config.ZoneConfig{
+		NumReplicas: proto.Int32(3),
+		Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{required("region", "east")}},
+		},
+	}
+	zones := []Zone{
+		makeZone(".default", config.ZoneConfig{
+			NumReplicas:   proto.Int32(3),
+			RangeMinBytes: proto.Int64(1 << 20),
+			RangeMaxBytes: proto.Int64(1 << 10),
+		}),
+		makeZone("db", dbZone),
+		makeZone("db.dup", dbZone),
+		makeZone("db.t", config.ZoneConfig{
+			Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{prohibited("dc", "1")}},
+			},
+		}),
+		makeZone("db.t@idx", config.ZoneConfig{
+			Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("dc", "1")}},
+			},
+		}),
+		makeZone("db.t.p", config.ZoneConfig{
+			Constraints: []config.Constraints{
+				{NumReplicas: 2, Constraints: []config.Constraint{required("region", "west")}},
+			},
+		}),
+		makeZone("db.u", config.ZoneConfig{
+			Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{prohibited("region", "west")}},
+			},
+		}),
+		makeZone("other.v", config.ZoneConfig{
+			Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("dc", "1")}},
+			},
+		}),
+	}
+	localities := []roachpb.Locality{
+		{Tiers: []roachpb.Tier{{Key: "region", Value: "east"}, {Key: "dc", Value: "1"}}},
+		{Tiers: []roachpb.Tier{{Key: "region", Value: "east"}, {Key: "dc", Value: "2"}}},
+		{Tiers: []roachpb.Tier{{Key: "region", Value: "west"}, {Key: "dc", Value: "3"}}},
+	}
+
+	expected := []string{
+		".default: range_max_bytes 1024 is less than range_min_bytes 1048576",
+		"db.dup: zone is identical to the zone of database db",
+		"db.t@idx: constraint +dc=1 contradicts the constraints of db.t",
+		"db.t.p: constraints [+region=west] require 2 node(s) but only 1 node(s) match",
+		"db.t.p: adding nodes with localities region=west would satisfy the constraints",
+		"db.t.p: constraint +region=west contradicts the constraints of db.u",
+	}
+	if actual := Lint(zones, localities); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, actual)
+	}
+
+	// Without localities, constraints are not checked for satisfiability.
+	expected = append(expected[:3], expected[5])
+	if actual := Lint(zones, nil); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, actual)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+var drainByLocality = settings.RegisterBoolSetting(
+	"server.shutdown.drain_by_locality.enabled",
+	"if enabled, sessions that benefit least from the locality of a draining node are "+
+		"canceled first, giving the others more time to finish",
+	false,
+)
+
+// drainPriority returns the priority with which a session is drained, between
+// 0 and 1. Sessions whose reads of indexes with replica constraints are not
+// local to this node do not benefit from the node's locality, so they can move
+// to another node at little cost and are drained first. Nothing is known about
+// sessions that have planned no such reads, so their priority is 0, and they
+// are left to the pgwire server like the sessions whose reads are all local.
+func drainPriority(s sql.SessionLocality) float64 {
+	return 1 - s.LocalReadFraction
+}
+
+// ZoneConstraintAwareDrainOrder returns the sessions in the order in which they
+// should be drained: by decreasing drain priority, so that the sessions that
+// benefit most from this node's locality are drained last. Sessions with the
+// same priority keep their relative order.
+func ZoneConstraintAwareDrainOrder(sessions []sql.SessionLocality) []sql.SessionLocality {
+	order := make([]sql.SessionLocality, len(sessions))
+	copy(order, sessions)
+	sort.SliceStable(order, func(i, j int) bool {
+		return drainPriority(order[i]) > drainPriority(order[j])
+	})
+	return order
+}
+
+// startDrainSessionsByLocality starts a task which cancels the sessions on this
+// node that do not fully benefit from its locality, in
+// ZoneConstraintAwareDrainOrder, spreading the cancellations evenly over the
+// first half of wait. The other sessions are left to the pgwire server, which
+// gives them all of wait to finish before canceling them. The task stops early
+// when done is closed.
+func (s *Server) startDrainSessionsByLocality(
+	ctx context.Context, wait time.Duration, done <-chan struct{},
+) {
+	var order []sql.SessionLocality
+	for _, session := range ZoneConstraintAwareDrainOrder(s.sessionRegistry.SessionLocalities()) {
+		if drainPriority(session) > 0 {
+			order = append(order, session)
+		}
+	}
+	if len(order) == 0 {
+		return
+	}
+
+	interval := wait / time.Duration(2*len(order))
+	if err := s.stopper.RunAsyncTask(ctx, "drain-sessions-by-locality", func(ctx context.Context) {
+		for _, session := range order {
+			select {
+			case <-time.After(interval):
+			case <-done:
+				return
+			}
+			// The session may have finished already, in which case it is no
+			// longer in the registry.
+			_, _ = s.sessionRegistry.CancelSession(session.ID.GetBytes(), security.RootUser)
+		}
+	}); err != nil {
+		log.Warningf(ctx, "unable to drain sessions by locality: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+)
+
+func TestZoneConstraintAwareDrainOrder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	session := func(id uint64, localReadFraction float64) sql.SessionLocality {
+		return sql.SessionLocality{
+			ID:                sql.ClusterWideID{Uint128: uint128.FromInts(0, id)},
+			LocalReadFraction: localReadFraction,
+		}
+	}
+
+	sessions := []sql.SessionLocality{
+		session(1, 1),
+		session(2, 0.5),
+		session(3, 0),
+		session(4, 1),
+		session(5, 0),
+	}
+	expected := []sql.SessionLocality{
+		session(3, 0),
+		session(5, 0),
+		session(2, 0.5),
+		session(1, 1),
+		session(4, 1),
+	}
+	if actual := ZoneConstraintAwareDrainOrder(sessions); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	// The input is not reordered.
+	if sessions[0] != session(1, 1) {
+		t.Errorf("expected input to be unchanged, got %v", sessions)
+	}
+}
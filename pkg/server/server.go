@@ -652,6 +652,9 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		),
 
 		QueryCache: querycache.New(s.cfg.SQLQueryCacheSize),
+
+		ZoneConfigVersion: &sql.ZoneConfigVersion{},
+		CrossRegionReads:  &sql.CrossRegionReadMonitor{},
 	}
 
 	if sqlSchemaChangerTestingKnobs := s.cfg.TestingKnobs.SQLSchemaChanger; sqlSchemaChangerTestingKnobs != nil {
@@ -1691,6 +1694,16 @@ func (s *Server) Start(ctx context.Context) error {
 	s.mux.Handle(loginPath, gwMux)
 	s.mux.Handle(logoutPath, authHandler)
 	s.mux.Handle(statusVars, http.HandlerFunc(s.status.handleVars))
+	var zoneAffinityHandler http.Handler = http.HandlerFunc(s.handleZoneAffinity)
+	if s.cfg.RequireWebSession() {
+		zoneAffinityHandler = newAuthenticationMux(s.authentication, zoneAffinityHandler)
+	}
+	s.mux.Handle(zoneAffinityPath, zoneAffinityHandler)
+	var zoneHealthHandler http.Handler = http.HandlerFunc(s.handleZoneHealth)
+	if s.cfg.RequireWebSession() {
+		zoneHealthHandler = newAuthenticationMux(s.authentication, zoneHealthHandler)
+	}
+	s.mux.Handle(zoneHealthPath, zoneHealthHandler)
 	log.Event(ctx, "added http endpoints")
 
 	// Attempt to upgrade cluster version.
@@ -1753,6 +1766,8 @@ func (s *Server) Start(ctx context.Context) error {
 	}
 
 	s.startSystemLogsGC(ctx)
+	s.startZoneConformanceReport(ctx)
+	s.startZoneSatisfiabilityCheck(ctx)
 
 	// Record that this node joined the cluster in the event log. Since this
 	// executes a SQL query, this must be done after the SQL layer is ready.
@@ -1822,6 +1837,11 @@ func (s *Server) doDrain(
 				}
 
 				drainMaxWait := queryWait.Get(&s.st.SV)
+				if drainByLocality.Get(&s.st.SV) {
+					done := make(chan struct{})
+					defer close(done)
+					s.startDrainSessionsByLocality(ctx, drainMaxWait, done)
+				}
 				if err := s.pgServer.Drain(drainMaxWait); err != nil {
 					return err
 				}
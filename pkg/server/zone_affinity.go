@@ -0,0 +1,103 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// zoneAffinityPath is the endpoint that returns the live nodes whose locality
+// satisfies a set of zone constraints. Every query parameter is a required
+// constraint, so for example
+//
+//   /api/v1/zones/affinity?region=us&dc=east
+//
+// returns the nodes with the constraints [+region=us,+dc=east]. A load balancer
+// can use it to route the connections of an application to the nodes that
+// hold its data. Without query parameters, all live nodes are returned.
+const zoneAffinityPath = "/api/v1/zones/affinity"
+
+// zoneAffinityResponse is the response of the zone affinity endpoint.
+type zoneAffinityResponse struct {
+	NodeIDs []roachpb.NodeID `json:"node_ids"`
+}
+
+// handleZoneAffinity serves zoneAffinityPath.
+func (s *Server) handleZoneAffinity(w http.ResponseWriter, r *http.Request) {
+	ctx := s.AnnotateCtx(r.Context())
+
+	query := r.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var constraints config.Constraints
+	for _, key := range keys {
+		for _, value := range query[key] {
+			constraints.Constraints = append(constraints.Constraints, config.Constraint{
+				Type: config.Constraint_REQUIRED, Key: key, Value: value,
+			})
+		}
+	}
+
+	var nodes []roachpb.NodeDescriptor
+	for nodeID, entry := range s.nodeLiveness.GetIsLiveMap() {
+		if !entry.IsLive {
+			continue
+		}
+		desc, err := s.gossip.GetNodeDescriptor(nodeID)
+		if err != nil {
+			// The node is not yet known to gossip.
+			continue
+		}
+		nodes = append(nodes, *desc)
+	}
+
+	body, err := marshalToJSON(zoneAffinityResponse{
+		NodeIDs: nodesSatisfyingConstraints(nodes, &constraints),
+	})
+	if err != nil {
+		log.Error(ctx, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(httputil.ContentTypeHeader, httputil.JSONContentType)
+	if _, err := w.Write(body); err != nil {
+		log.Warning(ctx, err)
+	}
+}
+
+// nodesSatisfyingConstraints returns the IDs of the nodes whose locality
+// satisfies the constraints, in increasing order.
+func nodesSatisfyingConstraints(
+	nodes []roachpb.NodeDescriptor, constraints cat.ConstraintSet,
+) []roachpb.NodeID {
+	nodeIDs := []roachpb.NodeID{}
+	for i := range nodes {
+		if cat.LocalitySatisfiesConstraints(nodes[i].Locality, constraints) {
+			nodeIDs = append(nodeIDs, nodes[i].NodeID)
+		}
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+	return nodeIDs
+}
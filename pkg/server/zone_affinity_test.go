@@ -0,0 +1,63 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestNodesSatisfyingConstraints(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	node := func(nodeID roachpb.NodeID, region, dc string) roachpb.NodeDescriptor {
+		return roachpb.NodeDescriptor{
+			NodeID: nodeID,
+			Locality: roachpb.Locality{Tiers: []roachpb.Tier{
+				{Key: "region", Value: region},
+				{Key: "dc", Value: dc},
+			}},
+		}
+	}
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	nodes := []roachpb.NodeDescriptor{
+		node(3, "us", "east"),
+		node(1, "us", "west"),
+		node(2, "eu", "east"),
+	}
+
+	testCases := []struct {
+		constraints []config.Constraint
+		expected    []roachpb.NodeID
+	}{
+		{nil, []roachpb.NodeID{1, 2, 3}},
+		{[]config.Constraint{required("region", "us")}, []roachpb.NodeID{1, 3}},
+		{[]config.Constraint{required("region", "us"), required("dc", "east")}, []roachpb.NodeID{3}},
+		{[]config.Constraint{required("region", "asia")}, []roachpb.NodeID{}},
+	}
+
+	for i, tc := range testCases {
+		actual := nodesSatisfyingConstraints(nodes, &config.Constraints{Constraints: tc.constraints})
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%d: expected %v, got %v", i, tc.expected, actual)
+		}
+	}
+}
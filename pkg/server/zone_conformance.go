@@ -0,0 +1,261 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// zoneConformanceReportInterval is the interval at which each node checks
+// the placement of replicas against the constraints of their zones.
+var zoneConformanceReportInterval = settings.RegisterNonNegativeDurationSetting(
+	"server.zone_constraint_report.interval",
+	"the interval at which replica placement is checked against zone constraints "+
+		"and a report is logged (0 to disable)",
+	5*time.Minute,
+)
+
+var metaZoneConstraintConformance = metric.Metadata{
+	Name:        "zone.constraint_conformance",
+	Help:        "Fraction of ranges with replica constraints and leases on this node whose replicas satisfy them, as of the last zone constraint report",
+	Measurement: "Ranges",
+	Unit:        metric.Unit_PERCENT,
+}
+
+const (
+	// zoneConformanceMaxLoggedRanges is the maximum number of nonconforming
+	// ranges that are listed in each report.
+	zoneConformanceMaxLoggedRanges = 10
+
+	// zoneConformanceAlertThreshold is the conformance below which a warning is
+	// logged for the ranges of an object.
+	zoneConformanceAlertThreshold = 0.95
+)
+
+// objectConformance counts the constrained ranges of one object, like a table
+// or a named zone's key span, and how many of them conform to the object's
+// zone.
+type objectConformance struct {
+	constrained   int
+	nonconforming int
+}
+
+// conformance returns the fraction of the object's constrained ranges that
+// conform to its zone.
+func (c objectConformance) conformance() float64 {
+	return float64(c.constrained-c.nonconforming) / float64(c.constrained)
+}
+
+// zoneConformanceReport summarizes how well the replica placement of the ranges
+// whose leases are held by a node conforms to zone constraints.
+type zoneConformanceReport struct {
+	// ranges is the number of ranges that were checked.
+	ranges int
+	// constrained is the number of ranges whose zones have replica
+	// constraints.
+	constrained int
+	// nonconforming lists the constrained ranges whose replicas do not satisfy
+	// their zones' constraints.
+	nonconforming []roachpb.RangeID
+	// objects counts the constrained ranges by the ID of the object whose zone
+	// applies to them. Ranges that do not belong to an object are counted
+	// under ID 0.
+	objects map[uint32]objectConformance
+}
+
+// conformance returns the fraction of constrained ranges that conform to their
+// zones' constraints, or 1 if no range is constrained.
+func (r *zoneConformanceReport) conformance() float64 {
+	if r.constrained == 0 {
+		return 1
+	}
+	return float64(r.constrained-len(r.nonconforming)) / float64(r.constrained)
+}
+
+// objectsBelow returns the IDs of the objects whose constrained ranges conform
+// to their zone less than the given fraction of the time, in increasing order.
+func (r *zoneConformanceReport) objectsBelow(threshold float64) []uint32 {
+	var res []uint32
+	for objectID, object := range r.objects {
+		if object.conformance() < threshold {
+			res = append(res, objectID)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+func (r *zoneConformanceReport) String() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%d of %d ranges with replica constraints conform to their zones (%d ranges checked)",
+		r.constrained-len(r.nonconforming), r.constrained, r.ranges)
+	if len(r.nonconforming) != 0 {
+		buf.WriteString("; nonconforming ranges: ")
+		for i, rangeID := range r.nonconforming {
+			if i == zoneConformanceMaxLoggedRanges {
+				fmt.Fprintf(&buf, ", and %d more", len(r.nonconforming)-i)
+				break
+			}
+			if i != 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "r%d", rangeID)
+		}
+	}
+	return buf.String()
+}
+
+// startZoneConformanceReport starts a worker which periodically checks the
+// placement of the replicas of the ranges whose leases are held by this node
+// against the constraints of their zones, logs a report, and updates the
+// zone.constraint_conformance gauge. Since each range is checked by its
+// leaseholder, every range is checked by one node, and no node scans meta2.
+func (s *Server) startZoneConformanceReport(ctx context.Context) {
+	gauge := metric.NewGaugeFloat64(metaZoneConstraintConformance)
+	gauge.Update(1)
+	s.registry.AddMetric(gauge)
+
+	s.stopper.RunWorker(ctx, func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			interval := zoneConformanceReportInterval.Get(&s.cfg.Settings.SV)
+			if interval == 0 {
+				// Check again later in case the report is enabled.
+				interval = time.Minute
+			}
+			timer.Reset(interval)
+			select {
+			case <-timer.C:
+				timer.Read = true
+			case <-s.stopper.ShouldStop():
+				return
+			}
+
+			if zoneConformanceReportInterval.Get(&s.cfg.Settings.SV) == 0 {
+				continue
+			}
+			report, err := s.checkZoneConformance(ctx)
+			if err != nil {
+				log.Warningf(ctx, "error checking zone constraint conformance: %v", err)
+				continue
+			}
+			gauge.Update(report.conformance())
+			log.Infof(ctx, "zone constraint report: %s", report)
+			for _, objectID := range report.objectsBelow(zoneConformanceAlertThreshold) {
+				object := report.objects[objectID]
+				log.Warningf(ctx, "only %d of %d ranges with replica constraints of object %d "+
+					"conform to its zone (%.1f%%)", object.constrained-object.nonconforming,
+					object.constrained, objectID, object.conformance()*100)
+			}
+		}
+	})
+}
+
+// checkZoneConformance checks the replicas of every range whose lease is held
+// by one of the node's stores against the replica constraints of its zone.
+func (s *Server) checkZoneConformance(ctx context.Context) (*zoneConformanceReport, error) {
+	report := &zoneConformanceReport{objects: make(map[uint32]objectConformance)}
+	var localities []roachpb.Locality
+	if err := s.node.stores.VisitStores(func(store *storage.Store) error {
+		now := store.Clock().Now()
+		store.VisitReplicas(func(repl *storage.Replica) bool {
+			if !repl.OwnsValidLease(now) {
+				return true
+			}
+			desc, zone := repl.DescAndZone()
+			report.ranges++
+			if zone.ReplicaConstraintsCount() == 0 {
+				return true
+			}
+			report.constrained++
+			objectID, _, _ := config.DecodeObjectID(desc.StartKey)
+			object := report.objects[objectID]
+			object.constrained++
+
+			localities = localities[:0]
+			for _, replica := range desc.Replicas {
+				// A node that is not known to gossip is treated as having no
+				// locality, so it satisfies no required constraints.
+				var locality roachpb.Locality
+				if nodeDesc, err := s.gossip.GetNodeDescriptor(replica.NodeID); err == nil {
+					locality = nodeDesc.Locality
+				}
+				localities = append(localities, locality)
+			}
+			if !replicasSatisfyZone(zone, localities) {
+				report.nonconforming = append(report.nonconforming, desc.RangeID)
+				object.nonconforming++
+			}
+			report.objects[objectID] = object
+			return true
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(report.nonconforming, func(i, j int) bool {
+		return report.nonconforming[i] < report.nonconforming[j]
+	})
+	return report, nil
+}
+
+// replicasSatisfyZone returns true if replicas in the given localities satisfy
+// the replica constraints of the zone.
+func replicasSatisfyZone(zone cat.Zone, localities []roachpb.Locality) bool {
+	return len(unsatisfiedReplicaConstraints(zone, localities)) == 0
+}
+
+// unsatisfiedReplicaConstraints returns the replica constraint sets of the zone
+// that replicas in the given localities do not satisfy. A set of constraints
+// that applies to all replicas is satisfied if every replica satisfies it, and
+// a set that applies to a number of replicas is satisfied if at least that many
+// replicas satisfy it. Replicas are not assigned to sets one-to-one, so a
+// replica may count toward several overlapping sets.
+func unsatisfiedReplicaConstraints(
+	zone cat.Zone, localities []roachpb.Locality,
+) []cat.ConstraintSet {
+	var unsatisfied []cat.ConstraintSet
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		set := zone.ReplicaConstraints(i)
+		satisfied := 0
+		for _, locality := range localities {
+			if cat.LocalitySatisfiesConstraints(locality, set) {
+				satisfied++
+			}
+		}
+		required := int(set.ReplicaCount())
+		if required == 0 {
+			required = len(localities)
+		}
+		if satisfied < required {
+			unsatisfied = append(unsatisfied, set)
+		}
+	}
+	return unsatisfied
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestReplicasSatisfyZone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	region := func(r string) roachpb.Locality {
+		return roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: r}}}
+	}
+
+	allEast := &config.ZoneConfig{Constraints: []config.Constraints{
+		{Constraints: []config.Constraint{required("region", "east")}},
+	}}
+	twoEastOneWest := &config.ZoneConfig{Constraints: []config.Constraints{
+		{NumReplicas: 2, Constraints: []config.Constraint{required("region", "east")}},
+		{NumReplicas: 1, Constraints: []config.Constraint{required("region", "west")}},
+	}}
+
+	testCases := []struct {
+		zone       *config.ZoneConfig
+		localities []roachpb.Locality
+		expected   bool
+	}{
+		{&config.ZoneConfig{}, []roachpb.Locality{region("west")}, true},
+		{allEast, []roachpb.Locality{region("east"), region("east"), region("east")}, true},
+		{allEast, []roachpb.Locality{region("east"), region("west"), region("east")}, false},
+		{allEast, []roachpb.Locality{region("east"), {}, region("east")}, false},
+		{twoEastOneWest, []roachpb.Locality{region("east"), region("west"), region("east")}, true},
+		{twoEastOneWest, []roachpb.Locality{region("east"), region("west"), region("west")}, false},
+		{twoEastOneWest, []roachpb.Locality{region("east"), region("east"), region("east")}, false},
+	}
+
+	for i, tc := range testCases {
+		if actual := replicasSatisfyZone(tc.zone, tc.localities); actual != tc.expected {
+			t.Errorf("%d: expected %t, got %t", i, tc.expected, actual)
+		}
+	}
+}
+
+func TestZoneConformanceObjectsBelow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	report := &zoneConformanceReport{objects: map[uint32]objectConformance{
+		0:  {constrained: 4},
+		52: {constrained: 20, nonconforming: 1},
+		53: {constrained: 20, nonconforming: 2},
+		54: {constrained: 1, nonconforming: 1},
+	}}
+	if actual, expected := report.objectsBelow(zoneConformanceAlertThreshold), []uint32{53, 54}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// zoneHealthPath is the endpoint that returns how well the replicas of the
+// ranges on this node conform to the constraints of their zones. Unlike
+// /health, it is subject to authentication when web sessions are required.
+const zoneHealthPath = "/health/zones"
+
+// zoneHealthMaxViolations is the number of most-violated constraints that
+// are returned by the zone health endpoint.
+const zoneHealthMaxViolations = 3
+
+// zoneHealthResponse is the response of the zone health endpoint.
+type zoneHealthResponse struct {
+	// Ranges is the number of ranges with a replica on this node.
+	Ranges int `json:"ranges"`
+	// ConstrainedRanges is the number of those ranges whose zones have replica
+	// constraints.
+	ConstrainedRanges int `json:"constrained_ranges"`
+	// ConformingFraction is the fraction of constrained ranges whose replicas
+	// satisfy their zones' constraints, or 1 if no range is constrained.
+	ConformingFraction float64 `json:"conforming_fraction"`
+	// MostViolatedConstraints lists the constraints that the most ranges
+	// violate, in decreasing order of the number of ranges.
+	MostViolatedConstraints []violatedConstraint `json:"most_violated_constraints"`
+}
+
+// violatedConstraint is a set of replica constraints, formatted like
+// [+region=east], and the number of ranges whose replicas violate it.
+type violatedConstraint struct {
+	Constraints string `json:"constraints"`
+	Ranges      int    `json:"ranges"`
+}
+
+// zoneHealthReport accumulates the zone health of a node's ranges.
+type zoneHealthReport struct {
+	ranges        int
+	constrained   int
+	nonconforming int
+	// violations counts the ranges that violate each constraint set, by the
+	// formatted set.
+	violations map[string]int
+}
+
+// add checks a range whose replicas are in the given localities against the
+// replica constraints of its zone.
+func (r *zoneHealthReport) add(zone cat.Zone, localities []roachpb.Locality) {
+	r.ranges++
+	if zone.ReplicaConstraintsCount() == 0 {
+		return
+	}
+	r.constrained++
+	unsatisfied := unsatisfiedReplicaConstraints(zone, localities)
+	if len(unsatisfied) == 0 {
+		return
+	}
+	r.nonconforming++
+	for _, set := range unsatisfied {
+		r.violations[cat.FormatConstraintSet(set)]++
+	}
+}
+
+func (r *zoneHealthReport) response() zoneHealthResponse {
+	res := zoneHealthResponse{
+		Ranges:                  r.ranges,
+		ConstrainedRanges:       r.constrained,
+		ConformingFraction:      1,
+		MostViolatedConstraints: []violatedConstraint{},
+	}
+	if r.constrained != 0 {
+		res.ConformingFraction = float64(r.constrained-r.nonconforming) / float64(r.constrained)
+	}
+	for constraints, ranges := range r.violations {
+		res.MostViolatedConstraints = append(res.MostViolatedConstraints, violatedConstraint{
+			Constraints: constraints, Ranges: ranges,
+		})
+	}
+	sort.Slice(res.MostViolatedConstraints, func(i, j int) bool {
+		left, right := res.MostViolatedConstraints[i], res.MostViolatedConstraints[j]
+		if left.Ranges != right.Ranges {
+			return left.Ranges > right.Ranges
+		}
+		return left.Constraints < right.Constraints
+	})
+	if len(res.MostViolatedConstraints) > zoneHealthMaxViolations {
+		res.MostViolatedConstraints = res.MostViolatedConstraints[:zoneHealthMaxViolations]
+	}
+	return res
+}
+
+// handleZoneHealth serves zoneHealthPath.
+func (s *Server) handleZoneHealth(w http.ResponseWriter, r *http.Request) {
+	ctx := s.AnnotateCtx(r.Context())
+
+	report := zoneHealthReport{violations: make(map[string]int)}
+	var localities []roachpb.Locality
+	if err := s.node.stores.VisitStores(func(store *storage.Store) error {
+		store.VisitReplicas(func(repl *storage.Replica) bool {
+			desc, zone := repl.DescAndZone()
+			localities = localities[:0]
+			for _, replica := range desc.Replicas {
+				// A node that is not known to gossip is treated as having no
+				// locality, so it satisfies no required constraints.
+				var locality roachpb.Locality
+				if nodeDesc, err := s.gossip.GetNodeDescriptor(replica.NodeID); err == nil {
+					locality = nodeDesc.Locality
+				}
+				localities = append(localities, locality)
+			}
+			report.add(zone, localities)
+			return true
+		})
+		return nil
+	}); err != nil {
+		log.Error(ctx, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	body, err := marshalToJSON(report.response())
+	if err != nil {
+		log.Error(ctx, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(httputil.ContentTypeHeader, httputil.JSONContentType)
+	if _, err := w.Write(body); err != nil {
+		log.Warning(ctx, err)
+	}
+}
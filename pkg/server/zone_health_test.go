@@ -0,0 +1,116 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestZoneHealthReport(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	region := func(r string) roachpb.Locality {
+		return roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: r}}}
+	}
+	allIn := func(r string) *config.ZoneConfig {
+		return &config.ZoneConfig{Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{required("region", r)}},
+		}}
+	}
+	east := []roachpb.Locality{region("east"), region("east"), region("east")}
+	west := []roachpb.Locality{region("west"), region("west"), region("west")}
+
+	report := zoneHealthReport{violations: make(map[string]int)}
+	report.add(&config.ZoneConfig{}, west)
+	report.add(allIn("east"), east)
+	report.add(allIn("east"), west)
+	report.add(allIn("east"), west)
+	report.add(allIn("west"), east)
+	report.add(allIn("north"), east)
+	report.add(allIn("south"), east)
+
+	res := report.response()
+	if res.Ranges != 7 || res.ConstrainedRanges != 6 {
+		t.Errorf("expected 7 ranges and 6 constrained ranges, got %+v", res)
+	}
+	if expected := 1.0 / 6; res.ConformingFraction != expected {
+		t.Errorf("expected conforming fraction %f, got %f", expected, res.ConformingFraction)
+	}
+	expected := []violatedConstraint{
+		{Constraints: "[+region=east]", Ranges: 2},
+		{Constraints: "[+region=north]", Ranges: 1},
+		{Constraints: "[+region=south]", Ranges: 1},
+	}
+	if !reflect.DeepEqual(res.MostViolatedConstraints, expected) {
+		t.Errorf("expected %+v, got %+v", expected, res.MostViolatedConstraints)
+	}
+
+	empty := zoneHealthReport{violations: make(map[string]int)}
+	if res := empty.response(); res.ConformingFraction != 1 || len(res.MostViolatedConstraints) != 0 {
+		t.Errorf("expected an empty report to conform, got %+v", res)
+	}
+}
+
+func TestZoneHealthEndpoint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+
+	client, err := s.GetAuthenticatedHTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(s.AdminURL() + zoneHealthPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{
+		"ranges", "constrained_ranges", "conforming_fraction", "most_violated_constraints",
+	} {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("expected field %q in response, got %v", field, fields)
+		}
+	}
+
+	var res zoneHealthResponse
+	if err := json.Unmarshal(fields["ranges"], &res.Ranges); err != nil {
+		t.Fatal(err)
+	}
+	if res.Ranges == 0 {
+		t.Error("expected the node to have ranges")
+	}
+}
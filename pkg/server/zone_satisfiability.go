@@ -0,0 +1,122 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// zoneSatisfiabilityCheckDelay is how long a node waits after it starts
+// before it checks whether the zone configs can be satisfied, which gives the
+// other nodes of the cluster time to gossip their localities.
+const zoneSatisfiabilityCheckDelay = time.Minute
+
+// startZoneSatisfiabilityCheck starts a worker which checks, once, that the
+// replica constraints of every zone config can be satisfied by the localities
+// of the nodes known to gossip, and logs a warning for each zone config that
+// cannot. Such zones usually come from a typo in a constraint, or from a
+// constraint on a region whose nodes have not been added yet, and their ranges
+// cannot be placed as configured.
+func (s *Server) startZoneSatisfiabilityCheck(ctx context.Context) {
+	s.stopper.RunWorker(ctx, func(ctx context.Context) {
+		select {
+		case <-time.After(zoneSatisfiabilityCheckDelay):
+		case <-s.stopper.ShouldStop():
+			return
+		}
+		select {
+		case <-s.gossip.RegisterSystemConfigChannel():
+		case <-s.stopper.ShouldStop():
+			return
+		}
+
+		var localities []roachpb.Locality
+		if err := s.gossip.IterateInfos(gossip.KeyNodeIDPrefix, func(_ string, info gossip.Info) error {
+			var desc roachpb.NodeDescriptor
+			if err := info.Value.GetProto(&desc); err != nil {
+				return err
+			}
+			localities = append(localities, desc.Locality)
+			return nil
+		}); err != nil {
+			log.Warningf(ctx, "error checking zone config satisfiability: %v", err)
+			return
+		}
+
+		unsatisfiable, err := unsatisfiableZones(s.gossip.GetSystemConfig(), localities)
+		if err != nil {
+			log.Warningf(ctx, "error checking zone config satisfiability: %v", err)
+			return
+		}
+		for _, zone := range unsatisfiable {
+			log.Warningf(ctx, "%s cannot be satisfied by the localities of the %d nodes in the cluster",
+				zone, len(localities))
+		}
+	})
+}
+
+// unsatisfiableZones returns a description of each zone config and subzone
+// config in the system config whose replica constraints cannot be satisfied by
+// nodes with the given localities, as determined by cat.IsSatisfiable.
+func unsatisfiableZones(
+	cfg *config.SystemConfig, localities []roachpb.Locality,
+) ([]string, error) {
+	zonesPrefix := encoding.EncodeUvarintAscending(
+		keys.MakeTablePrefix(keys.ZonesTableID), uint64(keys.ZonesTablePrimaryIndexID),
+	)
+
+	var res []string
+	for _, kv := range cfg.Values {
+		if !bytes.HasPrefix(kv.Key, zonesPrefix) {
+			continue
+		}
+		_, id, err := encoding.DecodeUvarintAscending(kv.Key[len(zonesPrefix):])
+		if err != nil {
+			return nil, err
+		}
+		var zone config.ZoneConfig
+		if err := kv.Value.GetProto(&zone); err != nil {
+			return nil, err
+		}
+
+		if !cat.IsSatisfiable(&zone, localities) {
+			res = append(res, fmt.Sprintf("zone config %d with constraints %s",
+				id, cat.ZoneConstraintSummary(&zone)))
+		}
+		for i := range zone.Subzones {
+			subzone := &zone.Subzones[i]
+			if !cat.IsSatisfiable(&subzone.Config, localities) {
+				target := fmt.Sprintf("index %d", subzone.IndexID)
+				if subzone.PartitionName != "" {
+					target = fmt.Sprintf("partition %s of %s", subzone.PartitionName, target)
+				}
+				res = append(res, fmt.Sprintf("zone config %d for %s with constraints %s",
+					id, target, cat.ZoneConstraintSummary(&subzone.Config)))
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestUnsatisfiableZones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	region := func(r string) roachpb.Locality {
+		return roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: r}}}
+	}
+	inRegion := func(r string) config.ZoneConfig {
+		return config.ZoneConfig{Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{required("region", r)}},
+		}}
+	}
+
+	zones := map[uint32]config.ZoneConfig{
+		keys.RootNamespaceID: config.DefaultZoneConfig(),
+		52:                   inRegion("east"),
+		53:                   inRegion("west"),
+		54: {
+			Subzones: []config.Subzone{
+				{IndexID: 1, PartitionName: "p", Config: inRegion("east")},
+				{IndexID: 2, Config: inRegion("west")},
+			},
+		},
+	}
+	cfg := config.NewSystemConfig()
+	for _, id := range []uint32{keys.RootNamespaceID, 52, 53, 54} {
+		zone := zones[id]
+		kv := roachpb.KeyValue{Key: config.MakeZoneKey(id)}
+		if err := kv.Value.SetProto(&zone); err != nil {
+			t.Fatal(err)
+		}
+		cfg.Values = append(cfg.Values, kv)
+	}
+
+	actual, err := unsatisfiableZones(cfg, []roachpb.Locality{region("east"), region("east")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"zone config 53 with constraints [+region=west]",
+		"zone config 54 for index 2 with constraints [+region=west]",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	actual, err = unsatisfiableZones(cfg, []roachpb.Locality{region("east"), region("west")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 0 {
+		t.Errorf("expected all zones to be satisfiable, got %v", actual)
+	}
+}
@@ -629,6 +629,12 @@ func (sc *SchemaChanger) distBackfill(
 					otherTableDescs = append(otherTableDescs, *table.TableDesc())
 				}
 			}
+			var zones []zoneSpans
+			if zoneConstraintAwareSchemaChange.Get(&sc.settings.SV) {
+				if zones, err = getBackfillZoneSpans(ctx, txn, tableDesc.TableDesc(), spans); err != nil {
+					return err
+				}
+			}
 			rw := &errOnlyResultWriter{}
 			recv := MakeDistSQLReceiver(
 				ctx,
@@ -645,7 +651,7 @@ func (sc *SchemaChanger) distBackfill(
 			defer recv.Release()
 			planCtx := sc.distSQLPlanner.NewPlanningCtx(ctx, evalCtx, txn)
 			plan, err := sc.distSQLPlanner.createBackfiller(
-				planCtx, backfillType, *tableDesc.TableDesc(), duration, chunkSize, spans, otherTableDescs, readAsOf,
+				planCtx, backfillType, *tableDesc.TableDesc(), duration, chunkSize, spans, zones, otherTableDescs, readAsOf,
 			)
 			if err != nil {
 				return err
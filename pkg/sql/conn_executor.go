@@ -317,6 +317,7 @@ func (s *Server) Start(ctx context.Context, stopper *stop.Stopper) {
 			case <-gossipUpdateC:
 				sysCfg := s.cfg.Gossip.GetSystemConfig()
 				s.dbCache.updateSystemConfig(sysCfg)
+				s.cfg.ZoneConfigVersion.update(sysCfg)
 			case <-stopper.ShouldStop():
 				return
 			}
@@ -446,6 +447,7 @@ func (s *Server) newSessionDataAndMutator(
 			Location: time.UTC,
 		},
 		ResultsBufferSize: args.ConnResultsBufferSize,
+		Locality:          args.Locality,
 	}
 
 	m := &sessionDataMutator{
@@ -901,6 +903,10 @@ type connExecutor struct {
 	// of the file.
 	ctxHolder ctxHolder
 
+	// readLocality counts the constrained index reads planned by the session.
+	// It is read concurrently by localReadFraction().
+	readLocality sessionReadLocality
+
 	// onCancelSession is called when the SessionRegistry is cancels this session.
 	// For pgwire connections, this is hooked up to canceling the connection's
 	// context.
@@ -937,6 +943,12 @@ type connExecutor struct {
 	// any. This is printed by high-level panic recovery.
 	curStmt tree.Statement
 
+	// asOfGCRetryTimestamp, if set, is the timestamp at which the next AS OF
+	// SYSTEM TIME statement in an implicit transaction reads instead of the
+	// one it specifies. It is set when such a statement is automatically
+	// retried after reading before a GC threshold. See retryWithZoneGCTTL.
+	asOfGCRetryTimestamp *hlc.Timestamp
+
 	sessionID ClusterWideID
 
 	// activated determines whether activate() was called already.
@@ -1908,6 +1920,7 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 
 	p.sessionDataMutator = ex.dataMutator
 	p.preparedStatements = ex.getPrepStmtsAccessor()
+	p.readLocality = &ex.readLocality
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -2083,6 +2096,11 @@ func (ex *connExecutor) user() string {
 	return ex.sessionData.User
 }
 
+// localReadFraction is part of the registrySession interface.
+func (ex *connExecutor) localReadFraction() float64 {
+	return ex.readLocality.localFraction()
+}
+
 // serialize is part of the registrySession interface.
 func (ex *connExecutor) serialize() serverpb.Session {
 	ex.mu.RLock()
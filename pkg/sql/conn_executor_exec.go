@@ -387,11 +387,17 @@ func (ex *connExecutor) execStmtInOpenState(
 		ex.resetPlanner(ctx, p, ex.state.mu.txn, stmtTS)
 	}
 
+	retriedAfterGC := false
 	if os.ImplicitTxn.Get() {
 		asOfTs, err := p.isAsOf(stmt.AST)
 		if err != nil {
 			return makeErrEvent(err)
 		}
+		if asOfTs != nil && ex.asOfGCRetryTimestamp != nil {
+			asOfTs = ex.asOfGCRetryTimestamp
+			retriedAfterGC = true
+		}
+		ex.asOfGCRetryTimestamp = nil
 		if asOfTs != nil {
 			p.semaCtx.AsOfTimestamp = asOfTs
 			p.extendedEvalCtx.SetTxnTimestamp(asOfTs.GoTime())
@@ -460,6 +466,28 @@ func (ex *connExecutor) execStmtInOpenState(
 		return nil, nil, err
 	}
 	if err := res.Err(); err != nil {
+		// If enabled, a historical read that ran into a GC threshold is retried
+		// once at a newer timestamp if the zones of the tables it reads allow
+		// it, as long as no results have been delivered to the client yet.
+		if os.ImplicitTxn.Get() && p.semaCtx.AsOfTimestamp != nil && !retriedAfterGC &&
+			zoneGCRetryEnabled.Get(&ex.server.cfg.Settings.SV) {
+			if retryTS, ok := ex.zoneGCRetryTimestamp(p, err); ok {
+				if rc, canAutoRetry := ex.getRewindTxnCapability(); canAutoRetry {
+					log.VEventf(ctx, 2, "retrying historical read at %s after: %v", retryTS, err)
+					ex.asOfGCRetryTimestamp = &retryTS
+					ev := eventRetriableErr{
+						IsCommit:     fsm.False,
+						CanAutoRetry: fsm.True,
+					}
+					payload := eventRetriableErrPayload{
+						err: ex.state.mu.txn.GenerateForcedRetryableError(
+							ctx, "historical read before GC threshold (detected by connExecutor)"),
+						rewCap: rc,
+					}
+					return ev, payload, nil
+				}
+			}
+		}
 		return makeErrEvent(err)
 	}
 
@@ -908,6 +936,9 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		res.SetError(err)
 		return nil
 	}
+	if planner.semaCtx.AsOfTimestamp != nil {
+		planner.curPlan.historicalTables = historicalReadTables(ctx, &planner.curPlan)
+	}
 
 	ex.sessionTracing.TracePlanCheckStart(ctx)
 	distributePlan := false
@@ -17,6 +17,7 @@ package sql
 import (
 	"bytes"
 	"context"
+	gojson "encoding/json"
 	"fmt"
 	"net"
 	"net/url"
@@ -35,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/status/statuspb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -85,6 +87,7 @@ var crdbInternal = virtualSchema{
 		sqlbase.CrdbInternalTableColumnsTableID:         crdbInternalTableColumnsTable,
 		sqlbase.CrdbInternalTableIndexesTableID:         crdbInternalTableIndexesTable,
 		sqlbase.CrdbInternalTablesTableID:               crdbInternalTablesTable,
+		sqlbase.CrdbInternalZoneConfigAuditLogTableID:   crdbInternalZoneConfigAuditLogTable,
 		sqlbase.CrdbInternalZonesTableID:                crdbInternalZonesTable,
 	},
 	validWithNoDatabaseContext: true,
@@ -351,23 +354,41 @@ var crdbInternalLeasesTable = virtualSchemaTable{
 	comment: `acquired table leases (RAM; local node only)`,
 	schema: `
 CREATE TABLE crdb_internal.leases (
-  node_id     INT NOT NULL,
-  table_id    INT NOT NULL,
-  name        STRING NOT NULL,
-  parent_id   INT NOT NULL,
-  expiration  TIMESTAMP NOT NULL,
-  deleted     BOOL NOT NULL
+  node_id          INT NOT NULL,
+  table_id         INT NOT NULL,
+  name             STRING NOT NULL,
+  parent_id        INT NOT NULL,
+  expiration       TIMESTAMP NOT NULL,
+  deleted          BOOL NOT NULL,
+  zone_constraints STRING,
+  lease_preference STRING
 )`,
 	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		leaseMgr := p.LeaseMgr()
 		nodeID := tree.NewDInt(tree.DInt(int64(leaseMgr.execCfg.NodeID.Get())))
 
+		// Use the gossiped zones rather than reading system.zones, so that no KV
+		// reads are issued while the lease manager is locked.
+		zones := NewCachedZoneConfigStore(p.execCfg.Gossip.GetSystemConfig())
+
 		leaseMgr.mu.Lock()
 		defer leaseMgr.mu.Unlock()
 
 		for tid, ts := range leaseMgr.mu.tables {
 			tableID := tree.NewDInt(tree.DInt(int64(tid)))
 
+			zoneConstraints, leasePreference := tree.DNull, tree.DNull
+			zone, err := zones.GetTableZone(ctx, cat.StableID(tid))
+			if err != nil {
+				return err
+			}
+			if s := zone.ConstraintString(); s != "" {
+				zoneConstraints = tree.NewDString(s)
+			}
+			if s := cat.FormatZoneLeasePreference(zone); s != "" {
+				leasePreference = tree.NewDString(s)
+			}
+
 			adder := func() error {
 				ts.mu.Lock()
 				defer ts.mu.Unlock()
@@ -392,6 +413,8 @@ CREATE TABLE crdb_internal.leases (
 						tree.NewDInt(tree.DInt(int64(state.GetParentID()))),
 						&lease.expiration,
 						dropped,
+						zoneConstraints,
+						leasePreference,
 					); err != nil {
 						return err
 					}
@@ -573,7 +596,8 @@ CREATE TABLE crdb_internal.node_statement_statistics (
   service_lat_avg     FLOAT NOT NULL,
   service_lat_var     FLOAT NOT NULL,
   overhead_lat_avg    FLOAT NOT NULL,
-  overhead_lat_var    FLOAT NOT NULL
+  overhead_lat_var    FLOAT NOT NULL,
+  gateway_region      STRING
 )`,
 	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		if err := p.RequireSuperUser(ctx, "access application statistics"); err != nil {
@@ -589,6 +613,20 @@ CREATE TABLE crdb_internal.node_statement_statistics (
 		leaseMgr := p.LeaseMgr()
 		nodeID := tree.NewDInt(tree.DInt(int64(leaseMgr.execCfg.NodeID.Get())))
 
+		// gateway_region is the region tier of this node's locality. Statistics
+		// are kept per node, and the locality of a node is fixed for the lifetime
+		// of its process, so every statement recorded here was planned with this
+		// node as the gateway. A session's crdb_locality override is not
+		// reflected: statistics are not kept per session, and statements with
+		// different overrides share the same entry.
+		gatewayRegion := tree.DNull
+		for _, tier := range p.ExecCfg().Locality.Tiers {
+			if tier.Key == cat.LocalityKeyRegion {
+				gatewayRegion = tree.NewDString(tier.Value)
+				break
+			}
+		}
+
 		// Retrieve the application names and sort them to ensure the
 		// output is deterministic.
 		var appNames []string
@@ -649,6 +687,7 @@ CREATE TABLE crdb_internal.node_statement_statistics (
 					tree.NewDFloat(tree.DFloat(s.data.ServiceLat.GetVariance(s.data.Count))),
 					tree.NewDFloat(tree.DFloat(s.data.OverheadLat.Mean)),
 					tree.NewDFloat(tree.DFloat(s.data.OverheadLat.GetVariance(s.data.Count))),
+					gatewayRegion,
 				)
 				s.Unlock()
 				if err != nil {
@@ -1066,6 +1105,26 @@ CREATE TABLE crdb_internal.builtin_functions (
 	},
 }
 
+// getAllZoneConfigs returns the zone configs stored in system.zones, keyed by
+// the ID of the object they apply to.
+func getAllZoneConfigs(ctx context.Context, p *planner) (map[uint32]*config.ZoneConfig, error) {
+	rows, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.Query(
+		ctx, "crdb-internal-get-all-zones", p.txn, `SELECT id, config FROM system.zones`)
+	if err != nil {
+		return nil, err
+	}
+	zones := make(map[uint32]*config.ZoneConfig, len(rows))
+	for _, r := range rows {
+		id := uint32(tree.MustBeDInt(r[0]))
+		var zone config.ZoneConfig
+		if err := protoutil.Unmarshal([]byte(*r[1].(*tree.DBytes)), &zone); err != nil {
+			return nil, err
+		}
+		zones[id] = &zone
+	}
+	return zones, nil
+}
+
 // crdbInternalCreateStmtsTable exposes the CREATE TABLE/CREATE VIEW
 // statements.
 var crdbInternalCreateStmtsTable = virtualSchemaTable{
@@ -1096,6 +1155,13 @@ CREATE TABLE crdb_internal.create_statements (
 		typeTable := tree.NewDString("table")
 		typeSequence := tree.NewDString("sequence")
 
+		// The CREATE TABLE statements describe the zones of indexes that have
+		// their own constraints, so load all zone configs up front.
+		zones, err := getAllZoneConfigs(ctx, p)
+		if err != nil {
+			return err
+		}
+
 		return forEachTableDescWithTableLookupInternal(ctx, p, dbContext, virtualOnce, true, /*allowAdding*/
 			func(db *DatabaseDescriptor, scName string, table *TableDescriptor, lCtx tableLookupFn) error {
 				parentNameStr := tree.DNull
@@ -1118,7 +1184,7 @@ CREATE TABLE crdb_internal.create_statements (
 				} else {
 					descType = typeTable
 					tn := (*tree.Name)(&table.Name)
-					createNofk, err = ShowCreateTable(ctx, tn, contextName, table, lCtx, true /* ignoreFKs */)
+					createNofk, err = ShowCreateTable(ctx, tn, contextName, table, lCtx, nil /* zone */, true /* ignoreFKs */)
 					if err != nil {
 						return err
 					}
@@ -1150,7 +1216,7 @@ CREATE TABLE crdb_internal.create_statements (
 							}
 						}
 					}
-					stmt, err = ShowCreateTable(ctx, tn, contextName, table, lCtx, false /* ignoreFKs */)
+					stmt, err = ShowCreateTable(ctx, tn, contextName, table, lCtx, zones[uint32(table.ID)], false /* ignoreFKs */)
 				}
 				if err != nil {
 					return err
@@ -1869,6 +1935,86 @@ CREATE TABLE crdb_internal.zones (
 	},
 }
 
+// crdbInternalZoneConfigAuditLogTable exposes the history of zone config
+// changes, from the set_zone_config events in system.eventlog. The table_id
+// column is the ID of the object whose zone was changed, which may also be a
+// database. The index_id column is NULL for zones that do not target an index
+// or partition, or if the index no longer exists. The constraints columns are
+// NULL if the event did not change the replica constraints of an existing
+// zone.
+var crdbInternalZoneConfigAuditLogTable = virtualSchemaTable{
+	comment: "history of zone configuration changes from system.eventlog (KV scan)",
+	schema: `
+CREATE TABLE crdb_internal.zone_config_audit_log (
+  timestamp          TIMESTAMP NOT NULL,
+  table_id           INT NOT NULL,
+  index_id           INT,
+  before_constraints STRING,
+  after_constraints  STRING,
+  changed_by         STRING
+)
+`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireSuperUser(ctx, "read crdb_internal.zone_config_audit_log"); err != nil {
+			return err
+		}
+
+		rows, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.Query(
+			ctx, "crdb-internal-zone-config-audit-log-table", p.txn,
+			`SELECT timestamp, "targetID", info FROM system.eventlog WHERE "eventType" = $1 ORDER BY timestamp`,
+			string(EventLogSetZoneConfig),
+		)
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			targetID := tree.MustBeDInt(r[1])
+			var info zoneConfigEventInfo
+			if s, ok := r[2].(*tree.DString); ok {
+				if err := gojson.Unmarshal([]byte(*s), &info); err != nil {
+					return err
+				}
+			}
+
+			indexID := tree.DNull
+			if zs, err := config.ParseCLIZoneSpecifier(info.Target); err == nil && zs.TargetsTable() {
+				// The table or its index may have been dropped since the zone
+				// was changed, in which case the index is unknown.
+				if table, err := sqlbase.GetTableDescFromID(ctx, p.txn, sqlbase.ID(targetID)); err == nil {
+					if index, _, err := resolveSubzone(ctx, p.txn, &zs, table.ID, table); err == nil && index != nil {
+						indexID = tree.NewDInt(tree.DInt(index.ID))
+					}
+				}
+			}
+
+			before, after := tree.DNull, tree.DNull
+			for _, change := range info.Changes {
+				if change.Field == "constraints" {
+					before = tree.NewDString(change.Before)
+					after = tree.NewDString(change.After)
+				}
+			}
+
+			changedBy := tree.DNull
+			if info.User != "" {
+				changedBy = tree.NewDString(info.User)
+			}
+
+			if err := addRow(
+				r[0],
+				tree.NewDInt(targetID),
+				indexID,
+				before,
+				after,
+				changedBy,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 // crdbInternalGossipNodesTable exposes local information about the cluster nodes.
 var crdbInternalGossipNodesTable = virtualSchemaTable{
 	comment: "locally known gossiped node details (RAM; local node only)",
@@ -0,0 +1,174 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+var crossRegionReadWarningBurst = settings.RegisterNonNegativeIntSetting(
+	"sql.cross_region_reads.warning_burst",
+	"number of cross-region reads of a table that may be planned in a burst before "+
+		"a warning is logged (0 to disable the warning)",
+	1000,
+)
+
+var crossRegionReadWarningRate = settings.RegisterNonNegativeFloatSetting(
+	"sql.cross_region_reads.warning_rate",
+	"number of cross-region reads of a table per second that may be planned "+
+		"without a warning being logged",
+	100,
+)
+
+// ZoneConstraintTokenBucket is a token bucket that counts the cross-region
+// reads of one table. A read is cross-region if the zone of the index it reads
+// does not fully match the locality of the gateway. Each read takes a token,
+// and tokens are refilled at a constant rate, so the bucket is only exhausted
+// if reads happen faster than that rate for long enough to use up the burst.
+type ZoneConstraintTokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+
+	// exhausted is set when a read finds the bucket empty, and is cleared when
+	// a read finds a token again. It is used to report each exhaustion once.
+	exhausted bool
+}
+
+// take refills the bucket for the time elapsed since the last call, keeping at
+// most capacity tokens, and then takes one token. It returns true if the bucket
+// has just become exhausted, which is the case if there is no token to take and
+// there was one during the previous call.
+func (b *ZoneConstraintTokenBucket) take(now time.Time, capacity, rate float64) bool {
+	if b.lastRefill.IsZero() {
+		b.tokens = capacity
+	} else if elapsed := now.Sub(b.lastRefill); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed.Seconds()*rate)
+	}
+	b.lastRefill = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		b.exhausted = false
+		return false
+	}
+	if b.exhausted {
+		return false
+	}
+	b.exhausted = true
+	return true
+}
+
+// CrossRegionReadMonitor keeps a ZoneConstraintTokenBucket for every table with
+// cross-region reads, and logs a warning when one of them is exhausted. This is
+// an early warning that misconfigured zone constraints are causing many reads
+// to leave the gateway's locality.
+type CrossRegionReadMonitor struct {
+	mu struct {
+		syncutil.Mutex
+		buckets map[sqlbase.ID]*ZoneConstraintTokenBucket
+	}
+}
+
+// noteRead records a planned read of the given index, logging a warning if
+// the read is cross-region and exhausts the table's bucket. Reads of indexes
+// without replica constraints are never cross-region, since nothing is known
+// about where their replicas are placed.
+func (m *CrossRegionReadMonitor) noteRead(
+	ctx context.Context, execCfg *ExecutorConfig, table cat.Table, index cat.Index,
+) {
+	if m == nil || len(execCfg.Locality.Tiers) == 0 {
+		return
+	}
+	sv := &execCfg.Settings.SV
+	burst := crossRegionReadWarningBurst.Get(sv)
+	if burst == 0 {
+		return
+	}
+	zone := index.Zone()
+	if zone.ReplicaConstraintsCount() == 0 || cat.LocalityScore(zone, execCfg.Locality) >= 1 {
+		return
+	}
+
+	tableID := sqlbase.ID(table.ID())
+	m.mu.Lock()
+	if m.mu.buckets == nil {
+		m.mu.buckets = make(map[sqlbase.ID]*ZoneConstraintTokenBucket)
+	}
+	b, ok := m.mu.buckets[tableID]
+	if !ok {
+		b = &ZoneConstraintTokenBucket{}
+		m.mu.buckets[tableID] = b
+	}
+	exhausted := b.take(timeutil.Now(), float64(burst), crossRegionReadWarningRate.Get(sv))
+	m.mu.Unlock()
+
+	if exhausted {
+		log.Warningf(ctx,
+			"more than %d cross-region reads of table %s were planned in a burst; "+
+				"index %s has constraints %s, but the gateway is in %s",
+			burst, table.Name(), index.Name(), cat.ZoneConstraintSummary(zone), execCfg.Locality)
+	}
+}
+
+// sessionReadLocality counts the reads of indexes with replica constraints that
+// have been planned by a session, and how many of them were of indexes whose
+// zones fully match the locality of the gateway. It is used to tell how much a
+// session benefits from the locality of its node. The counters are accessed
+// atomically, since they are read by other goroutines through the
+// SessionRegistry.
+type sessionReadLocality struct {
+	constrainedReads int64
+	localReads       int64
+}
+
+// noteRead records a planned read of an index in the given zone.
+func (l *sessionReadLocality) noteRead(zone cat.Zone, gatewayLocality roachpb.Locality) {
+	if l == nil || len(gatewayLocality.Tiers) == 0 || zone.ReplicaConstraintsCount() == 0 {
+		return
+	}
+	atomic.AddInt64(&l.constrainedReads, 1)
+	if cat.LocalityScore(zone, gatewayLocality) >= 1 {
+		atomic.AddInt64(&l.localReads, 1)
+	}
+}
+
+// localFraction returns the fraction of the constrained reads that were local
+// to the gateway, or 1 if there were none, since then no read was remote.
+func (l *sessionReadLocality) localFraction() float64 {
+	constrained := atomic.LoadInt64(&l.constrainedReads)
+	if constrained == 0 {
+		return 1
+	}
+	return float64(atomic.LoadInt64(&l.localReads)) / float64(constrained)
+}
+
+// noteIndexRead records a planned read of the given index, both in the read
+// locality of the planner's session and in the cross-region read monitor.
+func (p *planner) noteIndexRead(table cat.Table, index cat.Index) {
+	execCfg := p.ExecCfg()
+	p.readLocality.noteRead(index.Zone(), execCfg.Locality)
+	execCfg.CrossRegionReads.noteRead(p.EvalContext().Context, execCfg, table, index)
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestZoneConstraintTokenBucket(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const capacity, rate = 3, 2
+	var b ZoneConstraintTokenBucket
+	now := time.Unix(100, 0)
+
+	// The bucket starts full, so the first reads do not exhaust it.
+	for i := 0; i < capacity; i++ {
+		if b.take(now, capacity, rate) {
+			t.Fatalf("read %d: expected bucket to have tokens", i)
+		}
+	}
+
+	// The next read exhausts the bucket, but exhaustion is only reported once.
+	if !b.take(now, capacity, rate) {
+		t.Fatal("expected bucket to be exhausted")
+	}
+	if b.take(now, capacity, rate) {
+		t.Fatal("expected exhaustion to be reported once")
+	}
+
+	// Half a second refills one token.
+	now = now.Add(500 * time.Millisecond)
+	if b.take(now, capacity, rate) {
+		t.Fatal("expected bucket to be refilled")
+	}
+	if !b.take(now, capacity, rate) {
+		t.Fatal("expected bucket to be exhausted again")
+	}
+
+	// The bucket never holds more than its capacity.
+	now = now.Add(time.Hour)
+	for i := 0; i < capacity; i++ {
+		if b.take(now, capacity, rate) {
+			t.Fatalf("read %d: expected bucket to have tokens", i)
+		}
+	}
+	if !b.take(now, capacity, rate) {
+		t.Fatal("expected bucket to be exhausted after using its capacity")
+	}
+}
+
+func TestSessionReadLocality(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	zone := func(constraints string) cat.Zone {
+		var c config.ConstraintsList
+		if err := yaml.UnmarshalStrict([]byte(constraints), &c); err != nil {
+			t.Fatal(err)
+		}
+		return &config.ZoneConfig{Constraints: c.Constraints}
+	}
+	gateway := roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: "east"}}}
+
+	var l sessionReadLocality
+	if f := l.localFraction(); f != 1 {
+		t.Fatalf("expected 1 with no reads, got %f", f)
+	}
+
+	// Reads of indexes without replica constraints are not counted.
+	l.noteRead(zone("[]"), gateway)
+	if f := l.localFraction(); f != 1 {
+		t.Fatalf("expected 1 with no constrained reads, got %f", f)
+	}
+
+	l.noteRead(zone("[+region=east]"), gateway)
+	l.noteRead(zone("[+region=west]"), gateway)
+	l.noteRead(zone("[+region=east]"), gateway)
+	l.noteRead(zone("[-region=east]"), gateway)
+	if f := l.localFraction(); f != 0.5 {
+		t.Fatalf("expected 0.5, got %f", f)
+	}
+
+	// Nothing is counted without a gateway locality.
+	l.noteRead(zone("[+region=west]"), roachpb.Locality{})
+	if f := l.localFraction(); f != 0.5 {
+		t.Fatalf("expected 0.5, got %f", f)
+	}
+}
@@ -128,6 +128,25 @@ var planMergeJoins = settings.RegisterBoolSetting(
 	true,
 )
 
+// If true, the final stage of a grouped aggregation whose partial stages run
+// in more than one region is planned on the gateway, rather than being
+// distributed by hash across the nodes of the partial stages. Each partial
+// result then crosses regions at most once, on its way to the gateway.
+var planRegionalFinalAggregation = settings.RegisterBoolSetting(
+	"sql.distsql.zone_aware_aggregation.enabled",
+	"if set, grouped aggregations that read from more than one region are finalized on the gateway",
+	false,
+)
+
+// If true, the joiners of a distributed join with a single input stream from
+// a region that the other input is not read from are only planned on the nodes
+// of the other input. See zoneLocalityJoinNodes.
+var planZoneLocalityJoins = settings.RegisterBoolSetting(
+	"sql.distsql.zone_aware_joins.enabled",
+	"if set, distributed joins avoid shuffling the rows of a large input to the region of a small remote input",
+	false,
+)
+
 // livenessProvider provides just the methods of storage.NodeLiveness that the
 // DistSQLPlanner needs, to avoid importing all of storage.
 type livenessProvider interface {
@@ -689,6 +708,87 @@ func (h *distSQLNodeHealth) check(ctx context.Context, nodeID roachpb.NodeID) er
 	return nil
 }
 
+// streamsSpanRegions returns true if the result streams of the plan are on
+// nodes in more than one region, where the region of a node is the first tier
+// of its locality. It returns false if the locality of a node is not known.
+func (dsp *DistSQLPlanner) streamsSpanRegions(p *PhysicalPlan) bool {
+	var region roachpb.Tier
+	for i, resultProc := range p.ResultRouters {
+		desc, err := dsp.gossip.GetNodeDescriptor(p.Processors[resultProc].Node)
+		if err != nil {
+			return false
+		}
+		var nodeRegion roachpb.Tier
+		if len(desc.Locality.Tiers) > 0 {
+			nodeRegion = desc.Locality.Tiers[0]
+		}
+		if i == 0 {
+			region = nodeRegion
+		} else if nodeRegion != region {
+			return true
+		}
+	}
+	return false
+}
+
+// zoneLocalityJoinNodes picks the nodes on which to run the joiners of a
+// distributed join with the given input streams, based on the regions of the
+// inputs. The region of a node is the first tier of its locality.
+//
+// If one input has a single stream, as is usual for a small table, and that
+// stream is in a region that none of the streams of the other input are in,
+// then the joiners are only planned on the nodes of the other input. The rows
+// of the small input then cross regions once each, rather than a share of the
+// rows of the large input being hashed to the joiner in the small input's
+// region. If both inputs are read from the same region, the join is hash
+// distributed across all the nodes of both inputs, like findJoinProcessorNodes
+// does.
+//
+// The second return value is false if the joiners should be planned like
+// findJoinProcessorNodes does, including when the locality of a node is not
+// known.
+func (dsp *DistSQLPlanner) zoneLocalityJoinNodes(
+	leftRouters, rightRouters []distsqlplan.ProcessorIdx, processors []distsqlplan.Processor,
+) ([]roachpb.NodeID, bool) {
+	regions := func(routers []distsqlplan.ProcessorIdx) (map[roachpb.Tier]struct{}, bool) {
+		res := make(map[roachpb.Tier]struct{})
+		for _, pIdx := range routers {
+			desc, err := dsp.gossip.GetNodeDescriptor(processors[pIdx].Node)
+			if err != nil || len(desc.Locality.Tiers) == 0 {
+				return nil, false
+			}
+			res[desc.Locality.Tiers[0]] = struct{}{}
+		}
+		return res, true
+	}
+	leftRegions, ok := regions(leftRouters)
+	if !ok {
+		return nil, false
+	}
+	rightRegions, ok := regions(rightRouters)
+	if !ok {
+		return nil, false
+	}
+	isRemote := func(small, large map[roachpb.Tier]struct{}) bool {
+		for region := range small {
+			if _, ok := large[region]; ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	// If both inputs have a single stream, in different regions, the joiner is
+	// planned in the region of the left one.
+	if len(rightRouters) == 1 && isRemote(rightRegions, leftRegions) {
+		return getNodesOfRouters(leftRouters, processors), true
+	}
+	if len(leftRouters) == 1 && isRemote(leftRegions, rightRegions) {
+		return getNodesOfRouters(rightRouters, processors), true
+	}
+	return nil, false
+}
+
 // PartitionSpans finds out which nodes are owners for ranges touching the
 // given spans, and splits the spans according to owning nodes. The result is a
 // set of SpanPartitions (guaranteed one for each relevant node), which form a
@@ -1754,10 +1854,12 @@ func (dsp *DistSQLPlanner) addAggregators(
 		p.PlanToStreamColMap = identityMap(p.PlanToStreamColMap, len(aggregations))
 	}
 
-	if len(finalAggsSpec.GroupCols) == 0 || len(p.ResultRouters) == 1 {
-		// No GROUP BY, or we have a single stream. Use a single final aggregator.
-		// If the previous stage was all on a single node, put the final
-		// aggregator there. Otherwise, bring the results back on this node.
+	if len(finalAggsSpec.GroupCols) == 0 || len(p.ResultRouters) == 1 ||
+		(planRegionalFinalAggregation.Get(&dsp.st.SV) && dsp.streamsSpanRegions(p)) {
+		// No GROUP BY, or we have a single stream, or the streams come from
+		// more than one region. Use a single final aggregator. If the previous
+		// stage was all on a single node, put the final aggregator there.
+		// Otherwise, bring the results back on this node.
 		node := dsp.nodeDesc.NodeID
 		if prevStageNode != 0 {
 			node = prevStageNode
@@ -2206,6 +2308,11 @@ func (dsp *DistSQLPlanner) createPlanForJoin(
 	// Set up the output columns.
 	if numEq := len(n.pred.leftEqualityIndices); numEq != 0 {
 		nodes = findJoinProcessorNodes(leftRouters, rightRouters, p.Processors)
+		if planZoneLocalityJoins.Get(&dsp.st.SV) {
+			if zoneNodes, ok := dsp.zoneLocalityJoinNodes(leftRouters, rightRouters, p.Processors); ok {
+				nodes = zoneNodes
+			}
+		}
 
 		if planMergeJoins.Get(&dsp.st.SV) && len(n.mergeJoinOrdering) > 0 {
 			// TODO(radu): we currently only use merge joins when we have an ordering on
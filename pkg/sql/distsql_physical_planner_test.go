@@ -1200,3 +1200,145 @@ func TestCheckNodeHealth(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamsSpanRegions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	mockGossip := gossip.NewTest(roachpb.NodeID(1), nil /* rpcContext */, nil, /* grpcServer */
+		stopper, metric.NewRegistry())
+	for i, region := range []string{"east", "east", "west", ""} {
+		desc := &roachpb.NodeDescriptor{
+			NodeID:  roachpb.NodeID(i + 1),
+			Address: util.UnresolvedAddr{AddressField: fmt.Sprintf("addr%d", i+1)},
+		}
+		if region != "" {
+			desc.Locality = roachpb.Locality{Tiers: []roachpb.Tier{
+				{Key: "region", Value: region},
+				{Key: "dc", Value: fmt.Sprintf("dc%d", i+1)},
+			}}
+		}
+		if err := mockGossip.SetNodeDescriptor(desc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dsp := DistSQLPlanner{gossip: mockGossip}
+
+	testCases := []struct {
+		nodes    []roachpb.NodeID
+		expected bool
+	}{
+		{nodes: []roachpb.NodeID{1}, expected: false},
+		// The nodes only differ in their second locality tier.
+		{nodes: []roachpb.NodeID{1, 2}, expected: false},
+		{nodes: []roachpb.NodeID{1, 2, 3}, expected: true},
+		{nodes: []roachpb.NodeID{3, 4}, expected: true},
+		// Node 5 is not in gossip.
+		{nodes: []roachpb.NodeID{5, 3}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprint(tc.nodes), func(t *testing.T) {
+			var p PhysicalPlan
+			for _, nodeID := range tc.nodes {
+				p.ResultRouters = append(p.ResultRouters, p.AddProcessor(distsqlplan.Processor{Node: nodeID}))
+			}
+			if actual := dsp.streamsSpanRegions(&p); actual != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestZoneLocalityJoinNodes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	// Three nodes in two regions, and a fourth node without a locality.
+	mockGossip := gossip.NewTest(roachpb.NodeID(1), nil /* rpcContext */, nil, /* grpcServer */
+		stopper, metric.NewRegistry())
+	regions := []string{"east", "east", "west", ""}
+	for i, region := range regions {
+		desc := &roachpb.NodeDescriptor{
+			NodeID:  roachpb.NodeID(i + 1),
+			Address: util.UnresolvedAddr{AddressField: fmt.Sprintf("addr%d", i+1)},
+		}
+		if region != "" {
+			desc.Locality = roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: region}}}
+		}
+		if err := mockGossip.SetNodeDescriptor(desc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dsp := DistSQLPlanner{gossip: mockGossip}
+
+	// crossRegionStreams is the cost of a join plan: the number of streams from
+	// the input routers to the joiners that cross regions.
+	crossRegionStreams := func(
+		routers []distsqlplan.ProcessorIdx, processors []distsqlplan.Processor, nodes []roachpb.NodeID,
+	) int {
+		var n int
+		for _, pIdx := range routers {
+			for _, nodeID := range nodes {
+				if regions[processors[pIdx].Node-1] != regions[nodeID-1] {
+					n++
+				}
+			}
+		}
+		return n
+	}
+
+	testCases := []struct {
+		left, right []roachpb.NodeID
+		expected    []roachpb.NodeID
+	}{
+		// The small right input is remote.
+		{left: []roachpb.NodeID{1, 2}, right: []roachpb.NodeID{3}, expected: []roachpb.NodeID{1, 2}},
+		// The small left input is remote.
+		{left: []roachpb.NodeID{3}, right: []roachpb.NodeID{1, 2}, expected: []roachpb.NodeID{1, 2}},
+		// Both inputs are small.
+		{left: []roachpb.NodeID{3}, right: []roachpb.NodeID{1}, expected: []roachpb.NodeID{3}},
+		// Both inputs are in the same region.
+		{left: []roachpb.NodeID{1, 2}, right: []roachpb.NodeID{2}, expected: nil},
+		// The small input is in one of the regions of the large input.
+		{left: []roachpb.NodeID{1, 3}, right: []roachpb.NodeID{2}, expected: nil},
+		// Neither input is small.
+		{left: []roachpb.NodeID{1, 2}, right: []roachpb.NodeID{3, 3}, expected: nil},
+		// The locality of node 4 is not known.
+		{left: []roachpb.NodeID{1, 4}, right: []roachpb.NodeID{3}, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v-%v", tc.left, tc.right), func(t *testing.T) {
+			var p PhysicalPlan
+			var leftRouters, rightRouters []distsqlplan.ProcessorIdx
+			for _, nodeID := range tc.left {
+				leftRouters = append(leftRouters, p.AddProcessor(distsqlplan.Processor{Node: nodeID}))
+			}
+			for _, nodeID := range tc.right {
+				rightRouters = append(rightRouters, p.AddProcessor(distsqlplan.Processor{Node: nodeID}))
+			}
+
+			actual, ok := dsp.zoneLocalityJoinNodes(leftRouters, rightRouters, p.Processors)
+			if ok != (tc.expected != nil) || !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("expected %v, got %v (%t)", tc.expected, actual, ok)
+			}
+			if !ok {
+				return
+			}
+
+			routers := append(append([]distsqlplan.ProcessorIdx(nil), leftRouters...), rightRouters...)
+			defaultNodes := findJoinProcessorNodes(leftRouters, rightRouters, p.Processors)
+			defaultCost := crossRegionStreams(routers, p.Processors, defaultNodes)
+			cost := crossRegionStreams(routers, p.Processors, actual)
+			if cost >= defaultCost {
+				t.Errorf("expected fewer than %d cross-region streams on %v, got %d",
+					defaultCost, actual, cost)
+			}
+		})
+	}
+}
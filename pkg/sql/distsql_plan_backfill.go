@@ -15,13 +15,20 @@
 package sql
 
 import (
+	"context"
+	"sort"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/pkg/errors"
 )
 
@@ -51,9 +58,229 @@ func initBackfillerSpec(
 	return ret, nil
 }
 
+// zoneSpans is a set of spans to backfill that are covered by the same zone
+// config.
+type zoneSpans struct {
+	zone  *config.ZoneConfig
+	spans roachpb.Spans
+}
+
+// getBackfillZoneSpans groups the spans to backfill by the zone configs of the
+// table's partitions and indexes. If the table has partition-level zone
+// configs, the backfill is zone-constraint-aware: the work for each partition
+// is still planned on the leaseholders of its ranges, but the ranges whose
+// leaseholder is on a node that could not hold a replica under the partition's
+// constraints are reported in the trace.
+//
+// If the table has no partition-level zone configs, nil is returned and the
+// backfill is planned as usual.
+func getBackfillZoneSpans(
+	ctx context.Context, txn *client.Txn, desc *sqlbase.TableDescriptor, spans []roachpb.Span,
+) ([]zoneSpans, error) {
+	zone, err := getZoneConfigRaw(ctx, txn, desc.ID)
+	if err != nil {
+		return nil, err
+	}
+	if zone == nil || !hasPartitionSubzone(zone) {
+		return nil, nil
+	}
+	// The table's zone config, or the zone it inherits from if it only holds
+	// subzones, is the parent of each subzone and covers the remaining spans.
+	_, parent, _, err := GetZoneConfigInTxn(
+		ctx, txn, uint32(desc.ID), nil /* index */, "" /* partition */, false, /* getInheritedDefault */
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := splitSpansBySubzone(desc.ID, zone, spans)
+	var res []zoneSpans
+	for i := range groups {
+		if len(groups[i]) == 0 {
+			continue
+		}
+		if i == len(zone.Subzones) {
+			res = append(res, zoneSpans{zone: parent, spans: groups[i]})
+			continue
+		}
+		subzone := zone.Subzones[i]
+		if subzone.PartitionName != "" {
+			if indexSubzone := zone.GetSubzone(subzone.IndexID, ""); indexSubzone != nil {
+				subzone.Config.InheritFromParent(indexSubzone.Config)
+			}
+		}
+		subzone.Config.InheritFromParent(*parent)
+		res = append(res, zoneSpans{zone: &subzone.Config, spans: groups[i]})
+	}
+	return res, nil
+}
+
+// hasPartitionSubzone returns true if the zone config has a subzone for a
+// partition rather than a whole index.
+func hasPartitionSubzone(zone *config.ZoneConfig) bool {
+	for i := range zone.Subzones {
+		if zone.Subzones[i].PartitionName != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSpansBySubzone splits the spans of the table at the boundaries of the
+// zone config's subzone spans. The pieces covered by the i-th subzone are
+// returned in the i-th group, and the pieces not covered by any subzone are
+// returned in an extra, last group.
+func splitSpansBySubzone(
+	tableID sqlbase.ID, zone *config.ZoneConfig, spans []roachpb.Span,
+) []roachpb.Spans {
+	type piece struct {
+		span         roachpb.Span
+		subzoneIndex int32
+	}
+
+	prefix := roachpb.Key(keys.MakeTablePrefix(uint32(tableID)))
+	groups := make([]roachpb.Spans, len(zone.Subzones)+1)
+	rest := len(zone.Subzones)
+	var pieces []piece
+	for _, span := range spans {
+		pieces = pieces[:0]
+		for _, s := range zone.SubzoneSpans {
+			// Subzone spans are stored without the table prefix, and an unset
+			// EndKey implies Key.PrefixEnd().
+			subzoneSpan := roachpb.Span{Key: append(prefix[:len(prefix):len(prefix)], s.Key...)}
+			if len(s.EndKey) == 0 {
+				subzoneSpan.EndKey = subzoneSpan.Key.PrefixEnd()
+			} else {
+				subzoneSpan.EndKey = append(prefix[:len(prefix):len(prefix)], s.EndKey...)
+			}
+			if !span.Overlaps(subzoneSpan) {
+				continue
+			}
+			if subzoneSpan.Key.Compare(span.Key) < 0 {
+				subzoneSpan.Key = span.Key
+			}
+			if span.EndKey.Compare(subzoneSpan.EndKey) < 0 {
+				subzoneSpan.EndKey = span.EndKey
+			}
+			pieces = append(pieces, piece{span: subzoneSpan, subzoneIndex: s.SubzoneIndex})
+		}
+		sort.Slice(pieces, func(i, j int) bool {
+			return pieces[i].span.Key.Compare(pieces[j].span.Key) < 0
+		})
+
+		key := span.Key
+		for _, p := range pieces {
+			if key.Compare(p.span.Key) < 0 {
+				groups[rest] = append(groups[rest], roachpb.Span{Key: key, EndKey: p.span.Key})
+			}
+			groups[p.subzoneIndex] = append(groups[p.subzoneIndex], p.span)
+			key = p.span.EndKey
+		}
+		if key.Compare(span.EndKey) < 0 {
+			groups[rest] = append(groups[rest], roachpb.Span{Key: key, EndKey: span.EndKey})
+		}
+	}
+	return groups
+}
+
+// partitionBackfillSpans is like PartitionSpans, but if zones is not nil, the
+// spans of each zone whose leaseholder is on a node without a store that could
+// hold a replica under the zone's constraints are reported in the trace. They
+// are not moved to another node: the processor reads and writes through the KV
+// layer, so moving it would not change which replicas serve the backfill and
+// would only add a hop. See getBackfillZoneSpans.
+func (dsp *DistSQLPlanner) partitionBackfillSpans(
+	planCtx *PlanningCtx, spans []roachpb.Span, zones []zoneSpans,
+) ([]SpanPartition, error) {
+	if zones == nil {
+		return dsp.PartitionSpans(planCtx, spans)
+	}
+	stores, err := dsp.storeDescriptors()
+	if err != nil {
+		return nil, err
+	}
+	var partitions []SpanPartition
+	nodeMap := make(map[roachpb.NodeID]int)
+	for _, z := range zones {
+		zonePartitions, err := dsp.PartitionSpans(planCtx, z.spans)
+		if err != nil {
+			return nil, err
+		}
+		satisfying := nodesSatisfyingZone(stores, z.zone)
+		for _, p := range zonePartitions {
+			node := p.Node
+			if satisfying != nil && !satisfying[node] {
+				log.Eventf(planCtx.ctx, "backfill leaseholder node %d violates zone constraints %s",
+					node, z.zone.ConstraintString())
+			}
+			idx, ok := nodeMap[node]
+			if !ok {
+				idx = len(partitions)
+				partitions = append(partitions, SpanPartition{Node: node})
+				nodeMap[node] = idx
+			}
+			partitions[idx].Spans = append(partitions[idx].Spans, p.Spans...)
+		}
+	}
+	for _, p := range partitions {
+		sort.Slice(p.Spans, func(i, j int) bool {
+			return p.Spans[i].Key.Compare(p.Spans[j].Key) < 0
+		})
+	}
+	return partitions, nil
+}
+
+// storeDescriptors returns the descriptors of the stores gossiped in the
+// cluster.
+func (dsp *DistSQLPlanner) storeDescriptors() ([]roachpb.StoreDescriptor, error) {
+	var stores []roachpb.StoreDescriptor
+	if err := dsp.gossip.IterateInfos(gossip.KeyStorePrefix, func(key string, i gossip.Info) error {
+		var desc roachpb.StoreDescriptor
+		if err := i.Value.GetProto(&desc); err != nil {
+			return errors.Wrapf(err, "failed to parse value for key %q", key)
+		}
+		stores = append(stores, desc)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return stores, nil
+}
+
+// nodesSatisfyingZone returns the set of nodes with at least one of the given
+// stores that could hold a replica under the zone's constraints. A store
+// qualifies if it matches every constraint of at least one of the zone's
+// constraint sets, as the allocator checks with config.StoreMatchesConstraint.
+// If the zone has no constraints, nil is returned.
+func nodesSatisfyingZone(
+	stores []roachpb.StoreDescriptor, zone *config.ZoneConfig,
+) map[roachpb.NodeID]bool {
+	if len(zone.Constraints) == 0 {
+		return nil
+	}
+	nodes := make(map[roachpb.NodeID]bool)
+	for _, store := range stores {
+		for _, set := range zone.Constraints {
+			matches := true
+			for _, c := range set.Constraints {
+				if !config.StoreMatchesConstraint(store, c) {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				nodes[store.Node.NodeID] = true
+				break
+			}
+		}
+	}
+	return nodes
+}
+
 // createBackfiller generates a plan consisting of index/column backfiller
 // processors, one for each node that has spans that we are reading. The plan is
-// finalized.
+// finalized. If zones is not nil, the backfill is zone-constraint-aware; see
+// getBackfillZoneSpans.
 func (dsp *DistSQLPlanner) createBackfiller(
 	planCtx *PlanningCtx,
 	backfillType backfillType,
@@ -61,6 +288,7 @@ func (dsp *DistSQLPlanner) createBackfiller(
 	duration time.Duration,
 	chunkSize int64,
 	spans []roachpb.Span,
+	zones []zoneSpans,
 	otherTables []sqlbase.TableDescriptor,
 	readAsOf hlc.Timestamp,
 ) (PhysicalPlan, error) {
@@ -69,7 +297,7 @@ func (dsp *DistSQLPlanner) createBackfiller(
 		return PhysicalPlan{}, err
 	}
 
-	spanPartitions, err := dsp.PartitionSpans(planCtx, spans)
+	spanPartitions, err := dsp.partitionBackfillSpans(planCtx, spans, zones)
 	if err != nil {
 		return PhysicalPlan{}, err
 	}
@@ -16,15 +16,20 @@ package sql
 
 import (
 	"context"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 )
 
@@ -106,3 +111,154 @@ func TestDistBackfill(t *testing.T) {
 		curr = str[0]
 	}
 }
+
+func TestSplitSpansBySubzone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const tableID = 51
+	// suffix returns the key suffix of the primary index of the table for the
+	// given values, and key returns the full key.
+	suffix := func(vals ...int64) roachpb.Key {
+		k := encoding.EncodeUvarintAscending(nil, 1 /* indexID */)
+		for _, v := range vals {
+			k = encoding.EncodeVarintAscending(k, v)
+		}
+		return k
+	}
+	key := func(vals ...int64) roachpb.Key {
+		return append(roachpb.Key(keys.MakeTablePrefix(tableID)), suffix(vals...)...)
+	}
+
+	// The first subzone covers [10, 20) and the second covers the prefix 30 as
+	// well as [40, 50).
+	zone := &config.ZoneConfig{
+		Subzones: []config.Subzone{
+			{IndexID: 1, PartitionName: "p1"},
+			{IndexID: 1, PartitionName: "p2"},
+		},
+		SubzoneSpans: []config.SubzoneSpan{
+			{Key: suffix(10), EndKey: suffix(20), SubzoneIndex: 0},
+			{Key: suffix(30), SubzoneIndex: 1},
+			{Key: suffix(40), EndKey: suffix(50), SubzoneIndex: 1},
+		},
+	}
+
+	testCases := []struct {
+		spans    []roachpb.Span
+		expected [][]roachpb.Span
+	}{
+		{
+			// A span of the whole index is split at every boundary.
+			spans: []roachpb.Span{{Key: key(), EndKey: key().PrefixEnd()}},
+			expected: [][]roachpb.Span{
+				{{Key: key(10), EndKey: key(20)}},
+				{{Key: key(30), EndKey: key(30).PrefixEnd()}, {Key: key(40), EndKey: key(50)}},
+				{
+					{Key: key(), EndKey: key(10)},
+					{Key: key(20), EndKey: key(30)},
+					{Key: key(30).PrefixEnd(), EndKey: key(40)},
+					{Key: key(50), EndKey: key().PrefixEnd()},
+				},
+			},
+		},
+		{
+			// Spans that partially overlap subzones are trimmed.
+			spans: []roachpb.Span{
+				{Key: key(15), EndKey: key(25)},
+				{Key: key(45), EndKey: key(60)},
+			},
+			expected: [][]roachpb.Span{
+				{{Key: key(15), EndKey: key(20)}},
+				{{Key: key(45), EndKey: key(50)}},
+				{{Key: key(20), EndKey: key(25)}, {Key: key(50), EndKey: key(60)}},
+			},
+		},
+		{
+			// A span within a subzone is not split.
+			spans: []roachpb.Span{{Key: key(11), EndKey: key(12)}},
+			expected: [][]roachpb.Span{
+				{{Key: key(11), EndKey: key(12)}},
+				nil,
+				nil,
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		groups := splitSpansBySubzone(tableID, zone, tc.spans)
+		if len(groups) != len(tc.expected) {
+			t.Fatalf("%d: expected %d groups, got %d", i, len(tc.expected), len(groups))
+		}
+		for j := range groups {
+			if !reflect.DeepEqual(roachpb.Spans(tc.expected[j]), groups[j]) {
+				t.Errorf("%d: group %d: expected %s, got %s", i, j, tc.expected[j], groups[j])
+			}
+		}
+	}
+}
+
+func TestNodesSatisfyingZone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	store := func(nodeID roachpb.NodeID, region string, attrs ...string) roachpb.StoreDescriptor {
+		return roachpb.StoreDescriptor{
+			Attrs: roachpb.Attributes{Attrs: attrs},
+			Node: roachpb.NodeDescriptor{
+				NodeID:   nodeID,
+				Locality: roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: region}}},
+			},
+		}
+	}
+	// Node 2 only has an ssd attribute on its second store.
+	stores := []roachpb.StoreDescriptor{
+		store(1, "east"),
+		store(2, "west"),
+		store(2, "west", "ssd"),
+		store(3, "west"),
+	}
+	constraint := func(typ config.Constraint_Type, key, value string) config.Constraint {
+		return config.Constraint{Type: typ, Key: key, Value: value}
+	}
+	required, prohibited := config.Constraint_REQUIRED, config.Constraint_PROHIBITED
+
+	testCases := []struct {
+		constraints []config.Constraints
+		expected    map[roachpb.NodeID]bool
+	}{
+		{nil, nil},
+		{
+			[]config.Constraints{{Constraints: []config.Constraint{
+				constraint(required, "region", "west"),
+			}}},
+			map[roachpb.NodeID]bool{2: true, 3: true},
+		},
+		{
+			// Store attributes are matched, not just node attributes.
+			[]config.Constraints{{Constraints: []config.Constraint{
+				constraint(required, "", "ssd"),
+			}}},
+			map[roachpb.NodeID]bool{2: true},
+		},
+		{
+			[]config.Constraints{{Constraints: []config.Constraint{
+				constraint(prohibited, "region", "west"),
+			}}},
+			map[roachpb.NodeID]bool{1: true},
+		},
+		{
+			// A node qualifies if it satisfies any of the constraint sets.
+			[]config.Constraints{
+				{NumReplicas: 1, Constraints: []config.Constraint{constraint(required, "region", "east")}},
+				{NumReplicas: 1, Constraints: []config.Constraint{constraint(required, "", "ssd")}},
+			},
+			map[roachpb.NodeID]bool{1: true, 2: true},
+		},
+	}
+
+	for i, tc := range testCases {
+		zone := &config.ZoneConfig{Constraints: tc.constraints}
+		if nodes := nodesSatisfyingZone(stores, zone); !reflect.DeepEqual(tc.expected, nodes) {
+			t.Errorf("%d: expected %v, got %v", i, tc.expected, nodes)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"sync"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -35,6 +36,17 @@ import (
 // of these scans.
 const ParallelScanResultThreshold = 10000
 
+// zoneAwarePrefetch enables the ZoneConstraintAwarePrefetch mode of table
+// readers: scans with a limit hint do not let a batch cross a boundary between
+// the subzones of the table, such as its partitions, and start again from the
+// first batch size after one. Reads therefore do not go to a remote partition
+// before the limit has had a chance to be satisfied by the current one.
+var zoneAwarePrefetch = settings.RegisterBoolSetting(
+	"sql.distsql.zone_aware_prefetch.enabled",
+	"if enabled, scans with a limit do not prefetch rows across partition boundaries",
+	false,
+)
+
 // tableReader is the start of a computation flow; it performs KV operations to
 // retrieve rows for a table, runs a filter expression, and passes rows with the
 // desired column values to an output RowReceiver.
@@ -129,6 +141,9 @@ func newTableReader(
 	for i, s := range spec.Spans {
 		tr.spans[i] = s.Span
 	}
+	if tr.limitHint != 0 && !spec.Reverse && zoneAwarePrefetch.Get(&flowCtx.Settings.SV) {
+		tr.setPartitionBoundaries(flowCtx, spec.Table.ID)
+	}
 	tr.input = &rowFetcherWrapper{Fetcher: &tr.fetcher}
 
 	if sp := opentracing.SpanFromContext(flowCtx.EvalCtx.Ctx()); sp != nil && tracing.IsRecording(sp) {
@@ -139,6 +154,25 @@ func newTableReader(
 	return tr, nil
 }
 
+// setPartitionBoundaries sets the boundaries between the subzones of the table
+// on the fetcher, if the system config is available.
+func (tr *tableReader) setPartitionBoundaries(flowCtx *FlowCtx, tableID sqlbase.ID) {
+	if flowCtx.Gossip == nil {
+		return
+	}
+	cfg := flowCtx.Gossip.GetSystemConfig()
+	if cfg == nil {
+		return
+	}
+	boundaries, err := cfg.GetSubzoneBoundariesForObject(uint32(tableID))
+	if err != nil {
+		// The scan is still correct without the boundaries.
+		log.VEventf(flowCtx.EvalCtx.Ctx(), 1, "unable to get partition boundaries: %v", err)
+		return
+	}
+	tr.fetcher.SetPartitionBoundaries(boundaries)
+}
+
 // rowFetcherWrapper is used only by a tableReader to wrap calls to
 // Fetcher.NextRow() in a RowSource implementation.
 type rowFetcherWrapper struct {
@@ -377,6 +377,14 @@ type ExecutorConfig struct {
 	InternalExecutor *InternalExecutor
 	QueryCache       *querycache.C
 
+	// ZoneConfigVersion is incremented by the Server whenever the zones in the
+	// gossiped system config change. It may be nil in testing scenarios.
+	ZoneConfigVersion *ZoneConfigVersion
+
+	// CrossRegionReads warns about tables with many cross-region reads. It may
+	// be nil in testing scenarios.
+	CrossRegionReads *CrossRegionReadMonitor
+
 	TestingKnobs              ExecutorTestingKnobs
 	SchemaChangerTestingKnobs *SchemaChangerTestingKnobs
 	DistSQLRunTestingKnobs    *distsqlrun.TestingKnobs
@@ -832,6 +840,9 @@ type SessionArgs struct {
 	// client.
 	RemoteAddr            net.Addr
 	ConnResultsBufferSize int64
+	// Locality is the locality given by the client with the "crdb_locality"
+	// connection parameter, if any. See SessionData.Locality.
+	Locality roachpb.Locality
 }
 
 // isDefined returns true iff the SessionArgs is well-defined.
@@ -869,6 +880,10 @@ type registrySession interface {
 	user() string
 	cancelQuery(queryID ClusterWideID) bool
 	cancelSession()
+	// localReadFraction returns the fraction of the session's planned reads of
+	// indexes with replica constraints that were local to the gateway, or 1 if
+	// it has planned no such reads.
+	localReadFraction() float64
 	// serialize serializes a Session into a serverpb.Session
 	// that can be served over RPC.
 	serialize() serverpb.Session
@@ -920,6 +935,29 @@ func (r *SessionRegistry) CancelSession(sessionIDBytes []byte, username string)
 	return false, fmt.Errorf("session ID %s not found", sessionID)
 }
 
+// SessionLocality describes how much a session benefits from the locality of
+// its gateway node.
+type SessionLocality struct {
+	ID ClusterWideID
+	// LocalReadFraction is the fraction of the session's planned reads of
+	// indexes with replica constraints that were local to the gateway, or 1 if
+	// the session has planned no such reads.
+	LocalReadFraction float64
+}
+
+// SessionLocalities returns the SessionLocality of every session in the
+// registry.
+func (r *SessionRegistry) SessionLocalities() []SessionLocality {
+	r.Lock()
+	defer r.Unlock()
+
+	response := make([]SessionLocality, 0, len(r.sessions))
+	for id, s := range r.sessions {
+		response = append(response, SessionLocality{ID: id, LocalReadFraction: s.localReadFraction()})
+	}
+	return response
+}
+
 // SerializeAll returns a slice of all sessions in the registry, converted to serverpb.Sessions.
 func (r *SessionRegistry) SerializeAll() []serverpb.Session {
 	r.Lock()
@@ -21,6 +21,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/treeprinter"
@@ -388,6 +389,7 @@ func (e *explainer) observer() planObserver {
 		expr:      e.expr,
 		attr:      e.attr,
 		spans:     e.spans,
+		zone:      e.zone,
 		leaveNode: e.leaveNode,
 	}
 }
@@ -405,6 +407,20 @@ func (e *explainer) spans(
 	}
 }
 
+// zone implements the planObserver interface. The zone is only shown with
+// metadata, and is shown as "default" if it has no replica constraints. It is
+// not shown if it is not known, as for scans planned by the heuristic planner.
+func (e *explainer) zone(nodeName, fieldName string, zone cat.Zone) {
+	if !e.showMetadata || zone == nil {
+		return
+	}
+	zoneStr := "default"
+	if s := zone.ConstraintString(); s != "" {
+		zoneStr = s
+	}
+	e.attr(nodeName, fieldName, zoneStr)
+}
+
 // expr implements the planObserver interface.
 func (e *explainer) expr(v observeVerbosity, nodeName, fieldName string, n int, expr tree.Expr) {
 	if expr != nil {
@@ -98,4 +98,19 @@ type Catalog interface {
 	// CheckPrivilege verifies that the current user has the given privilege on
 	// the given catalog object. If not, then CheckPrivilege returns an error.
 	CheckPrivilege(ctx context.Context, o Object, priv privilege.Kind) error
+
+	// ZoneConfigVersion returns a counter that increases each time any zone
+	// config changes. A query plan that was built when the counter had a
+	// different value might no longer be optimal for the placement of the data
+	// it accesses, and so should be rebuilt.
+	ZoneConfigVersion() uint64
+
+	// TablesWithZoneConfig returns the tables in the database with the given
+	// name that have a zone config of their own, including tables that only
+	// have zone configs on their indexes or partitions. Tables that only
+	// inherit the zone of their database or the default zone are not returned.
+	// The current user's privileges on the tables are not checked.
+	//
+	// If no such database exists, then TablesWithZoneConfig returns an error.
+	TablesWithZoneConfig(ctx context.Context, dbName string) ([]Table, error)
 }
@@ -0,0 +1,54 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
+)
+
+func TestTablesWithZoneConfig(t *testing.T) {
+	catalog := testcat.New()
+	for _, ddl := range []string{
+		"CREATE TABLE a (a INT PRIMARY KEY)",
+		"CREATE TABLE b (b INT PRIMARY KEY, c INT, INDEX b_c_idx (c))",
+		"CREATE TABLE c (c INT PRIMARY KEY)",
+		"ALTER INDEX c@primary CONFIGURE ZONE USING constraints='[+region=east]'",
+		"ALTER INDEX b@b_c_idx CONFIGURE ZONE USING constraints='[+region=west]'",
+	} {
+		if _, err := catalog.ExecuteDDL(ddl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tables, err := catalog.TablesWithZoneConfig(context.Background(), "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tab := range tables {
+		names = append(names, string(tab.Name().TableName))
+	}
+	if expected := []string{"b", "c"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected tables %v, got %v", expected, names)
+	}
+
+	if _, err := catalog.TablesWithZoneConfig(context.Background(), "other"); err == nil {
+		t.Error("expected an error for a database that does not exist")
+	}
+}
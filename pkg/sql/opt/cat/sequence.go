@@ -28,6 +28,11 @@ type Sequence interface {
 	// safety measure so that every DataSource does not trivially implement
 	// Sequence.
 	SequenceName() *tree.TableName
+
+	// Zone returns the zone which constrains placement of the sequence's range
+	// replicas. If the sequence was not explicitly assigned to a zone, then it
+	// inherits the zone of its owning database or the default zone.
+	Zone() Zone
 }
 
 // FormatSequence nicely formats a catalog sequence using a treeprinter for
@@ -17,7 +17,11 @@ package cat
 import (
 	"bytes"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/treeprinter"
 )
 
@@ -25,6 +29,11 @@ import (
 // The optimizer prefers indexes with constraints that best match the locality
 // of the gateway node that plans the query.
 type Zone interface {
+	// ReplicaCount returns the number of replicas of each range in the zone. If
+	// 0, then the zone does not set a replica count and instead inherits it from
+	// its parent zone.
+	ReplicaCount() int32
+
 	// ReplicaConstraintsCount returns the number of replica constraint sets that
 	// are part of this zone.
 	ReplicaConstraintsCount() int
@@ -32,6 +41,33 @@ type Zone interface {
 	// ReplicaConstraints returns the ith set of replica constraints in the zone,
 	// where i < ReplicaConstraintsCount.
 	ReplicaConstraints(i int) ReplicaConstraints
+
+	// LeasePreferenceCount returns the number of lease preferences that are part
+	// of this zone.
+	LeasePreferenceCount() int
+
+	// LeasePreference returns the ith lease preference in the zone, where
+	// i < LeasePreferenceCount. Lease preferences are ordered from most
+	// preferred to least preferred.
+	LeasePreference(i int) ConstraintSet
+
+	// ConstraintString returns the first non-empty set of replica constraints
+	// in the zone, formatted like [+region=east,-dc=west], or the empty string
+	// if the zone has no replica constraints. Implementations can return
+	// FormatZoneConstraints of themselves.
+	ConstraintString() string
+}
+
+// ConstraintSet is a set of constraints that apply to a range, restricting
+// which nodes can host that range or stating which nodes are preferred as the
+// leaseholder.
+type ConstraintSet interface {
+	// ConstraintCount returns the number of constraints in the set.
+	ConstraintCount() int
+
+	// Constraint returns the ith constraint in the set, where
+	// i < ConstraintCount.
+	Constraint(i int) Constraint
 }
 
 // ReplicaConstraints is a set of constraints that apply to one or more replicas
@@ -39,17 +75,12 @@ type Zone interface {
 // table range has three replicas, then two of the replicas might be pinned to
 // nodes in one region, whereas the third might be pinned to another region.
 type ReplicaConstraints interface {
+	ConstraintSet
+
 	// ReplicaCount returns the number of replicas that should abide by this set
 	// of constraints. If 0, then the constraints apply to all replicas of the
 	// range (and there can be only one ReplicaConstraints in the Zone).
 	ReplicaCount() int32
-
-	// ConstraintCount returns the number of constraints in the set.
-	ConstraintCount() int
-
-	// Constraint returns the ith constraint in the set, where
-	// i < ConstraintCount.
-	Constraint(i int) Constraint
 }
 
 // Constraint governs placement of range replicas on nodes. A constraint can
@@ -66,6 +97,11 @@ type Constraint interface {
 	// a prohibited constraint (signified by initial + or - character).
 	IsRequired() bool
 
+	// IsProhibited is true if this is a prohibited constraint. A constraint that
+	// is neither required nor prohibited is a deprecated positive constraint,
+	// which only expresses a preference for nodes that match it.
+	IsProhibited() bool
+
 	// GetKey returns the constraint's string key (to left of =).
 	GetKey() string
 
@@ -73,6 +109,34 @@ type Constraint interface {
 	GetValue() string
 }
 
+// EffectiveNumReplicas returns the number of replicas of each range in the
+// zone. If the zone does not set a replica count and instead inherits it, then
+// EffectiveNumReplicas returns the given default, which is typically the
+// replica count of the cluster's default zone.
+func EffectiveNumReplicas(zone Zone, clusterDefault int32) int32 {
+	if numReplicas := zone.ReplicaCount(); numReplicas != 0 {
+		return numReplicas
+	}
+	return clusterDefault
+}
+
+// IsPinned returns true if at least one set of replica constraints in the zone
+// has a required constraint, meaning that the replicas it applies to can only be
+// placed on matching nodes. A zone that only has lease preferences or
+// prohibited constraints is not pinned, since its replicas may still be placed
+// anywhere that is not excluded.
+func IsPinned(zone Zone) bool {
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		set := zone.ReplicaConstraints(i)
+		for j, m := 0, set.ConstraintCount(); j < m; j++ {
+			if set.Constraint(j).IsRequired() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // FormatZone nicely formats a catalog zone using a treeprinter for debugging
 // and testing.
 func FormatZone(zone Zone, tp treeprinter.Node) {
@@ -92,25 +156,289 @@ func FormatZone(zone Zone, tp treeprinter.Node) {
 	}
 }
 
-func formatReplicaConstraint(replConstraint ReplicaConstraints) string {
+// FormatZoneConstraints returns the first non-empty set of replica constraints
+// in the zone, formatted like [+region=east,-dc=west]. If the zone has no
+// replica constraints, then FormatZoneConstraints returns the empty string.
+// This is a short form of the zone's placement that is suitable for log lines
+// and error messages; use FormatZone for the complete zone.
+func FormatZoneConstraints(zone Zone) string {
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		replConstraint := zone.ReplicaConstraints(i)
+		if replConstraint.ConstraintCount() != 0 {
+			return formatReplicaConstraint(replConstraint)
+		}
+	}
+	return ""
+}
+
+// FormatZoneLeasePreference returns the first lease preference in the zone,
+// formatted like [+region=east,-dc=west]. If the zone has no lease preferences,
+// then FormatZoneLeasePreference returns the empty string.
+func FormatZoneLeasePreference(zone Zone) string {
+	if zone.LeasePreferenceCount() == 0 {
+		return ""
+	}
+	return formatReplicaConstraint(zone.LeasePreference(0))
+}
+
+// ZoneConstraintSummary renders all replica constraints of the zone on one
+// line, using the syntax of the constraints zone config field. Constraints that
+// apply to all replicas are rendered as a list, like [+region=east,-dc=west].
+// Per-replica constraints are rendered as a map from constraints to replica
+// counts, like {+region=east: 2, +region=west: 1}. If the zone has no replica
+// constraints, then ZoneConstraintSummary returns [].
+func ZoneConstraintSummary(zone Zone) string {
+	n := zone.ReplicaConstraintsCount()
+	if n == 0 {
+		return "[]"
+	}
+	if n == 1 && zone.ReplicaConstraints(0).ReplicaCount() == 0 {
+		return formatReplicaConstraint(zone.ReplicaConstraints(0))
+	}
+	var buf bytes.Buffer
+	buf.WriteRune('{')
+	for i := 0; i < n; i++ {
+		replConstraint := zone.ReplicaConstraints(i)
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		writeConstraints(&buf, replConstraint)
+		fmt.Fprintf(&buf, ": %d", replConstraint.ReplicaCount())
+	}
+	buf.WriteRune('}')
+	return buf.String()
+}
+
+// ZoneReplicaSummary renders the replica constraints of the zone along with
+// the number of replicas each set applies to, for use in error messages, like
+// {+region=us-east: 2 replicas, +region=us-west: 1 replica}. A set that
+// applies to all replicas is rendered like {+region=us-east: all replicas}. If
+// the zone has no replica constraints, then ZoneReplicaSummary returns {}.
+//
+// Unlike ZoneConstraintSummary, the result is not valid zone config syntax.
+func ZoneReplicaSummary(zone Zone) string {
+	var buf bytes.Buffer
+	buf.WriteRune('{')
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		replConstraint := zone.ReplicaConstraints(i)
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		writeConstraints(&buf, replConstraint)
+		buf.WriteString(": ")
+		buf.WriteString(describeReplicaCount(replConstraint.ReplicaCount()))
+	}
+	buf.WriteRune('}')
+	return buf.String()
+}
+
+// describeReplicaCount describes the replicas that a replica constraint set
+// with the given count applies to, like "2 replicas". A count of 0 applies to
+// all replicas.
+func describeReplicaCount(count int32) string {
+	switch count {
+	case 0:
+		return "all replicas"
+	case 1:
+		return "1 replica"
+	default:
+		return fmt.Sprintf("%d replicas", count)
+	}
+}
+
+// FormatZoneAsComment formats the replica constraints of the zone as a SQL
+// block comment, for documenting the zone of a schema object inline in its
+// definition. For example:
+//
+//   /* zone: [+region=us-east, 2 replicas], [+region=us-west, 1 replica] */
+//
+// A set that applies to all replicas has no replica count, like
+// /* zone: [+region=us-east] */. If the zone has no replica constraints, then
+// FormatZoneAsComment returns /* zone: [] */. Any */ or /* in a constraint is
+// broken up so that it cannot end the comment early or, since block comments
+// nest, open a comment that the final */ would close instead.
+func FormatZoneAsComment(zone Zone) string {
+	var buf bytes.Buffer
+	n := zone.ReplicaConstraintsCount()
+	if n == 0 {
+		buf.WriteString("[]")
+	}
+	for i := 0; i < n; i++ {
+		replConstraint := zone.ReplicaConstraints(i)
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteRune('[')
+		writeConstraints(&buf, replConstraint)
+		if count := replConstraint.ReplicaCount(); count != 0 {
+			buf.WriteString(", ")
+			buf.WriteString(describeReplicaCount(count))
+		}
+		buf.WriteRune(']')
+	}
+	escaped := strings.Replace(buf.String(), "*/", "* /", -1)
+	escaped = strings.Replace(escaped, "/*", "/ *", -1)
+	return "/* zone: " + escaped + " */"
+}
+
+// ZoneToJSON returns a JSON representation of the replica count, replica
+// constraints and lease preferences of the zone, for use in machine-readable
+// reports. For example:
+//
+//   {
+//     "constraints": [
+//       {"constraints": ["+region=us-east"], "num_replicas": 2},
+//       {"constraints": ["+region=us-west"], "num_replicas": 1}
+//     ],
+//     "lease_preferences": [["+region=us-east"]],
+//     "num_replicas": 3
+//   }
+//
+// A replica count of 0 means that the count is inherited, or, for a replica
+// constraint set, that the set applies to all replicas.
+func ZoneToJSON(zone Zone) json.JSON {
+	constraints := json.NewArrayBuilder(zone.ReplicaConstraintsCount())
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		replConstraint := zone.ReplicaConstraints(i)
+		b := json.NewObjectBuilder(2)
+		b.Add("constraints", constraintSetToJSON(replConstraint))
+		b.Add("num_replicas", json.FromInt(int(replConstraint.ReplicaCount())))
+		constraints.Add(b.Build())
+	}
+	leasePreferences := json.NewArrayBuilder(zone.LeasePreferenceCount())
+	for i, n := 0, zone.LeasePreferenceCount(); i < n; i++ {
+		leasePreferences.Add(constraintSetToJSON(zone.LeasePreference(i)))
+	}
+
+	b := json.NewObjectBuilder(3)
+	b.Add("constraints", constraints.Build())
+	b.Add("lease_preferences", leasePreferences.Build())
+	b.Add("num_replicas", json.FromInt(int(zone.ReplicaCount())))
+	return b.Build()
+}
+
+// constraintSetToJSON returns the constraints in the set as a JSON array of
+// strings, like ["+region=east", "-dc=west"]. Deprecated positive constraints
+// have no prefix.
+func constraintSetToJSON(set ConstraintSet) json.JSON {
+	b := json.NewArrayBuilder(set.ConstraintCount())
+	for i, n := 0, set.ConstraintCount(); i < n; i++ {
+		constraint := set.Constraint(i)
+		prefix := ""
+		if constraint.IsRequired() {
+			prefix = "+"
+		} else if constraint.IsProhibited() {
+			prefix = "-"
+		}
+		b.Add(json.FromString(prefix + formatConstraintValue(constraint)))
+	}
+	return b.Build()
+}
+
+// FormatReplicaConstraintsAsSQL formats the replica constraint set as a SQL
+// string literal that can be used as the value of the constraints field in
+// CONFIGURE ZONE USING, like '{"+region=east,-dc=1": 2}'. A set that applies to
+// all replicas has no replica count to map to, so it is formatted as a list
+// instead, like '[+region=east,-dc=1]'.
+func FormatReplicaConstraintsAsSQL(rc ReplicaConstraints) string {
+	if rc.ReplicaCount() == 0 {
+		return lex.EscapeSQLString(formatReplicaConstraint(rc))
+	}
+	var buf bytes.Buffer
+	writeConstraints(&buf, rc)
+	return lex.EscapeSQLString(fmt.Sprintf("{%s: %d}", strconv.Quote(buf.String()), rc.ReplicaCount()))
+}
+
+// ValidateZone returns an error if any replica constraint set or lease
+// preference in the zone can never be satisfied because it both requires and
+// prohibits the same key/value pair.
+//
+// If a replica constraint set is invalid, the error includes the
+// ZoneReplicaSummary of the zone, so that the set can be told apart from the
+// zone's other sets.
+func ValidateZone(zone Zone) error {
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		if err := validateConstraintSet(zone.ReplicaConstraints(i)); err != nil {
+			return fmt.Errorf("%v (replica constraints: %s)", err, ZoneReplicaSummary(zone))
+		}
+	}
+	for i, n := 0, zone.LeasePreferenceCount(); i < n; i++ {
+		if err := validateConstraintSet(zone.LeasePreference(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateConstraintSet(set ConstraintSet) error {
+	for i, n := 0, set.ConstraintCount(); i < n; i++ {
+		left := set.Constraint(i)
+		for j := i + 1; j < n; j++ {
+			right := set.Constraint(j)
+			if left.GetKey() == right.GetKey() && left.GetValue() == right.GetValue() &&
+				left.IsProhibited() != right.IsProhibited() {
+				return fmt.Errorf("constraints %s both require and prohibit %s",
+					formatReplicaConstraint(set), formatConstraintValue(left))
+			}
+		}
+	}
+	return nil
+}
+
+// FormatConstraintSet renders the constraints in the set as a list, like
+// [+region=east,-dc=west]. The number of replicas the set applies to is not
+// included.
+func FormatConstraintSet(set ConstraintSet) string {
+	return formatReplicaConstraint(set)
+}
+
+func formatReplicaConstraint(replConstraint ConstraintSet) string {
 	var buf bytes.Buffer
 	buf.WriteRune('[')
-	for i, n := 0, replConstraint.ConstraintCount(); i < n; i++ {
-		constraint := replConstraint.Constraint(i)
+	writeConstraints(&buf, replConstraint)
+	buf.WriteRune(']')
+	return buf.String()
+}
+
+// writeConstraints writes the constraints in the set to buf, separated by
+// commas, like +region=east,-dc=west. Deprecated positive constraints have no
+// prefix, as in config.Constraint.String.
+func writeConstraints(buf *bytes.Buffer, set ConstraintSet) {
+	for i, n := 0, set.ConstraintCount(); i < n; i++ {
+		constraint := set.Constraint(i)
 		if i != 0 {
 			buf.WriteRune(',')
 		}
 		if constraint.IsRequired() {
 			buf.WriteRune('+')
-		} else {
+		} else if constraint.IsProhibited() {
 			buf.WriteRune('-')
 		}
-		if constraint.GetKey() != "" {
-			fmt.Fprintf(&buf, "%s=%s", constraint.GetKey(), constraint.GetValue())
-		} else {
-			buf.WriteString(constraint.GetValue())
+		buf.WriteString(formatConstraintValue(constraint))
+	}
+}
+
+// formatZoneLeasePreferences formats the lease preferences of the zone using
+// the syntax of the lease_preferences zone config field, like
+// [[+region=east], [+region=west]].
+func formatZoneLeasePreferences(zone Zone) string {
+	var buf bytes.Buffer
+	buf.WriteRune('[')
+	for i, n := 0, zone.LeasePreferenceCount(); i < n; i++ {
+		if i != 0 {
+			buf.WriteString(", ")
 		}
+		buf.WriteString(formatReplicaConstraint(zone.LeasePreference(i)))
 	}
 	buf.WriteRune(']')
 	return buf.String()
 }
+
+// formatConstraintValue formats the key/value pair of a constraint as key=value,
+// or as just the value if the key is empty.
+func formatConstraintValue(constraint Constraint) string {
+	if constraint.GetKey() != "" {
+		return fmt.Sprintf("%s=%s", constraint.GetKey(), constraint.GetValue())
+	}
+	return constraint.GetValue()
+}
@@ -0,0 +1,398 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import "sort"
+
+// AddConstraint returns a zone that is the same as the given zone, except that
+// c is appended to its first set of replica constraints. If the zone has no
+// replica constraints, then the returned zone has a single set that applies to
+// all replicas and contains only c.
+//
+// The given zone is not modified. The returned zone is a view over it rather
+// than a copy, so it must not be used after the given zone is changed.
+func AddConstraint(zone Zone, c Constraint) Zone {
+	return &augmentedZone{Zone: zone, constraint: c}
+}
+
+// augmentedZone is a Zone with an extra constraint in its first set of replica
+// constraints. See AddConstraint.
+type augmentedZone struct {
+	Zone
+	constraint Constraint
+}
+
+var _ Zone = &augmentedZone{}
+
+// ReplicaConstraintsCount is part of the Zone interface.
+func (z *augmentedZone) ReplicaConstraintsCount() int {
+	if n := z.Zone.ReplicaConstraintsCount(); n != 0 {
+		return n
+	}
+	return 1
+}
+
+// ReplicaConstraints is part of the Zone interface.
+func (z *augmentedZone) ReplicaConstraints(i int) ReplicaConstraints {
+	if i != 0 {
+		return z.Zone.ReplicaConstraints(i)
+	}
+	res := &augmentedReplicaConstraints{constraint: z.constraint}
+	if z.Zone.ReplicaConstraintsCount() != 0 {
+		res.base = z.Zone.ReplicaConstraints(0)
+	}
+	return res
+}
+
+// ConstraintString is part of the Zone interface.
+func (z *augmentedZone) ConstraintString() string {
+	return FormatZoneConstraints(z)
+}
+
+// augmentedReplicaConstraints is a ReplicaConstraints with an extra constraint
+// appended to it. If base is nil, then the set applies to all replicas and
+// contains only the extra constraint.
+type augmentedReplicaConstraints struct {
+	base       ReplicaConstraints
+	constraint Constraint
+}
+
+var _ ReplicaConstraints = &augmentedReplicaConstraints{}
+
+// ReplicaCount is part of the ReplicaConstraints interface.
+func (c *augmentedReplicaConstraints) ReplicaCount() int32 {
+	if c.base == nil {
+		return 0
+	}
+	return c.base.ReplicaCount()
+}
+
+// ConstraintCount is part of the ConstraintSet interface.
+func (c *augmentedReplicaConstraints) ConstraintCount() int {
+	if c.base == nil {
+		return 1
+	}
+	return c.base.ConstraintCount() + 1
+}
+
+// Constraint is part of the ConstraintSet interface.
+func (c *augmentedReplicaConstraints) Constraint(i int) Constraint {
+	if c.base != nil && i < c.base.ConstraintCount() {
+		return c.base.Constraint(i)
+	}
+	return c.constraint
+}
+
+// WithConstraintFor returns a zone that is the same as the given zone, except
+// that a constraint with the given key and value is appended to its first set
+// of replica constraints, as with AddConstraint. The constraint is required if
+// required is true, and prohibited otherwise. If key is empty, then the
+// constraint is on a store attribute, like -ssd. Together with
+// WithNumReplicas, it can be used to build zones in a single expression:
+//
+//   WithNumReplicas(WithConstraintFor(zone, "region", "east", true), 3)
+//
+func WithConstraintFor(zone Zone, key, value string, required bool) Zone {
+	return AddConstraint(zone, &keyValueConstraint{required: required, key: key, value: value})
+}
+
+// keyValueConstraint is a Constraint built from its parts. See
+// WithConstraintFor.
+type keyValueConstraint struct {
+	required   bool
+	key, value string
+}
+
+var _ Constraint = &keyValueConstraint{}
+
+// IsRequired is part of the Constraint interface.
+func (c *keyValueConstraint) IsRequired() bool {
+	return c.required
+}
+
+// IsProhibited is part of the Constraint interface.
+func (c *keyValueConstraint) IsProhibited() bool {
+	return !c.required
+}
+
+// GetKey is part of the Constraint interface.
+func (c *keyValueConstraint) GetKey() string {
+	return c.key
+}
+
+// GetValue is part of the Constraint interface.
+func (c *keyValueConstraint) GetValue() string {
+	return c.value
+}
+
+// WithNumReplicas returns a zone that is the same as the given zone, except
+// that its replica count is n. Like AddConstraint, the given zone is not
+// modified, and the returned zone is a view over it.
+func WithNumReplicas(zone Zone, n int32) Zone {
+	return &zoneWithNumReplicas{Zone: zone, numReplicas: n}
+}
+
+// zoneWithNumReplicas is a Zone with a different replica count than the zone
+// it wraps. See WithNumReplicas.
+type zoneWithNumReplicas struct {
+	Zone
+	numReplicas int32
+}
+
+var _ Zone = &zoneWithNumReplicas{}
+
+// ReplicaCount is part of the Zone interface.
+func (z *zoneWithNumReplicas) ReplicaCount() int32 {
+	return z.numReplicas
+}
+
+// WithReplicaConstraintFor returns a zone that is the same as the given zone,
+// except that a new replica constraint set is appended to it. The set applies
+// to numReplicas replicas and contains a single constraint with the given key
+// and value, which is required if required is true, and prohibited otherwise.
+// Like AddConstraint, the given zone is not modified, and the returned zone is
+// a view over it.
+func WithReplicaConstraintFor(
+	zone Zone, numReplicas int32, key, value string, required bool,
+) Zone {
+	return &zoneWithReplicaConstraints{
+		Zone: zone,
+		replConstraints: &augmentedReplicaConstraints{
+			base:       &emptyReplicaConstraints{replicaCount: numReplicas},
+			constraint: &keyValueConstraint{required: required, key: key, value: value},
+		},
+	}
+}
+
+// zoneWithReplicaConstraints is a Zone with an extra replica constraint set
+// after the sets of the zone it wraps. See WithReplicaConstraintFor.
+type zoneWithReplicaConstraints struct {
+	Zone
+	replConstraints ReplicaConstraints
+}
+
+var _ Zone = &zoneWithReplicaConstraints{}
+
+// ReplicaConstraintsCount is part of the Zone interface.
+func (z *zoneWithReplicaConstraints) ReplicaConstraintsCount() int {
+	return z.Zone.ReplicaConstraintsCount() + 1
+}
+
+// ReplicaConstraints is part of the Zone interface.
+func (z *zoneWithReplicaConstraints) ReplicaConstraints(i int) ReplicaConstraints {
+	if i < z.Zone.ReplicaConstraintsCount() {
+		return z.Zone.ReplicaConstraints(i)
+	}
+	return z.replConstraints
+}
+
+// ConstraintString is part of the Zone interface.
+func (z *zoneWithReplicaConstraints) ConstraintString() string {
+	return FormatZoneConstraints(z)
+}
+
+// emptyReplicaConstraints is a ReplicaConstraints with no constraints.
+type emptyReplicaConstraints struct {
+	replicaCount int32
+}
+
+var _ ReplicaConstraints = &emptyReplicaConstraints{}
+
+// ReplicaCount is part of the ReplicaConstraints interface.
+func (c *emptyReplicaConstraints) ReplicaCount() int32 {
+	return c.replicaCount
+}
+
+// ConstraintCount is part of the ConstraintSet interface.
+func (c *emptyReplicaConstraints) ConstraintCount() int {
+	return 0
+}
+
+// Constraint is part of the ConstraintSet interface.
+func (c *emptyReplicaConstraints) Constraint(i int) Constraint {
+	panic("replica constraint set has no constraints")
+}
+
+// WithLeasePreferenceFor returns a zone that is the same as the given zone,
+// except that a lease preference that requires the given key and value is
+// appended to it, so it is the least preferred. Like AddConstraint, the given
+// zone is not modified, and the returned zone is a view over it.
+func WithLeasePreferenceFor(zone Zone, key, value string) Zone {
+	return &zoneWithLeasePreference{
+		Zone: zone,
+		leasePref: &augmentedReplicaConstraints{
+			constraint: &keyValueConstraint{required: true, key: key, value: value},
+		},
+	}
+}
+
+// zoneWithLeasePreference is a Zone with an extra lease preference after the
+// lease preferences of the zone it wraps. See WithLeasePreferenceFor.
+type zoneWithLeasePreference struct {
+	Zone
+	leasePref ConstraintSet
+}
+
+var _ Zone = &zoneWithLeasePreference{}
+
+// LeasePreferenceCount is part of the Zone interface.
+func (z *zoneWithLeasePreference) LeasePreferenceCount() int {
+	return z.Zone.LeasePreferenceCount() + 1
+}
+
+// LeasePreference is part of the Zone interface.
+func (z *zoneWithLeasePreference) LeasePreference(i int) ConstraintSet {
+	if i < z.Zone.LeasePreferenceCount() {
+		return z.Zone.LeasePreference(i)
+	}
+	return z.leasePref
+}
+
+// WithoutLeasePreferences returns a zone that is the same as the given zone,
+// except that it has no lease preferences. It can be used to check whether
+// zones are equivalent regardless of their lease preferences:
+//
+//   ZoneCompare(WithoutLeasePreferences(a), WithoutLeasePreferences(b)) == 0
+//
+// Like AddConstraint, the given zone is not modified, and the returned zone is
+// a view over it.
+func WithoutLeasePreferences(zone Zone) Zone {
+	return &zoneWithoutLeasePreferences{Zone: zone}
+}
+
+// zoneWithoutLeasePreferences is a Zone that hides the lease preferences of
+// the zone it wraps. See WithoutLeasePreferences.
+type zoneWithoutLeasePreferences struct {
+	Zone
+}
+
+var _ Zone = &zoneWithoutLeasePreferences{}
+
+// LeasePreferenceCount is part of the Zone interface.
+func (z *zoneWithoutLeasePreferences) LeasePreferenceCount() int {
+	return 0
+}
+
+// LeasePreference is part of the Zone interface.
+func (z *zoneWithoutLeasePreferences) LeasePreference(i int) ConstraintSet {
+	panic("zone has no lease preferences")
+}
+
+// NormalizeZone returns a zone that is equivalent to the given zone, but whose
+// constraints are in a canonical order, so that zones entered with the same
+// constraints in a different order compare, hash, and diff as equal. The
+// constraints within each replica constraint set and lease preference are
+// sorted by key, then by value, and finally with required constraints before
+// prohibited ones. The replica constraint sets are then sorted by replica count
+// and by their sorted constraints. Lease preferences are ordered from most to
+// least preferred, so their order is kept.
+//
+// Like AddConstraint, the given zone is not modified, and the returned zone is
+// a view over it.
+func NormalizeZone(zone Zone) Zone {
+	res := &normalizedZone{Zone: zone}
+	if n := zone.ReplicaConstraintsCount(); n != 0 {
+		res.replConstraints = make([]sortedReplicaConstraints, n)
+		for i := range res.replConstraints {
+			replConstraint := zone.ReplicaConstraints(i)
+			res.replConstraints[i] = sortedReplicaConstraints{
+				sortedConstraintSet: makeSortedConstraintSet(replConstraint),
+				replicaCount:        replConstraint.ReplicaCount(),
+			}
+		}
+		sort.SliceStable(res.replConstraints, func(i, j int) bool {
+			return compareReplicaConstraints(&res.replConstraints[i], &res.replConstraints[j]) < 0
+		})
+	}
+	if n := zone.LeasePreferenceCount(); n != 0 {
+		res.leasePrefs = make([]sortedConstraintSet, n)
+		for i := range res.leasePrefs {
+			res.leasePrefs[i] = makeSortedConstraintSet(zone.LeasePreference(i))
+		}
+	}
+	return res
+}
+
+// normalizedZone is a Zone whose constraints are in canonical order. See
+// NormalizeZone.
+type normalizedZone struct {
+	Zone
+	replConstraints []sortedReplicaConstraints
+	leasePrefs      []sortedConstraintSet
+}
+
+var _ Zone = &normalizedZone{}
+
+// ReplicaConstraints is part of the Zone interface.
+func (z *normalizedZone) ReplicaConstraints(i int) ReplicaConstraints {
+	return &z.replConstraints[i]
+}
+
+// LeasePreference is part of the Zone interface.
+func (z *normalizedZone) LeasePreference(i int) ConstraintSet {
+	return &z.leasePrefs[i]
+}
+
+// ConstraintString is part of the Zone interface.
+func (z *normalizedZone) ConstraintString() string {
+	return FormatZoneConstraints(z)
+}
+
+// sortedConstraintSet is a ConstraintSet whose constraints are sorted using
+// compareConstraints.
+type sortedConstraintSet struct {
+	base ConstraintSet
+
+	// order maps from the position of each constraint in the sorted set to its
+	// position in base.
+	order []int
+}
+
+var _ ConstraintSet = &sortedConstraintSet{}
+
+func makeSortedConstraintSet(base ConstraintSet) sortedConstraintSet {
+	order := make([]int, base.ConstraintCount())
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return compareConstraints(base.Constraint(order[i]), base.Constraint(order[j])) < 0
+	})
+	return sortedConstraintSet{base: base, order: order}
+}
+
+// ConstraintCount is part of the ConstraintSet interface.
+func (c *sortedConstraintSet) ConstraintCount() int {
+	return len(c.order)
+}
+
+// Constraint is part of the ConstraintSet interface.
+func (c *sortedConstraintSet) Constraint(i int) Constraint {
+	return c.base.Constraint(c.order[i])
+}
+
+// sortedReplicaConstraints is a ReplicaConstraints whose constraints are sorted
+// using compareConstraints.
+type sortedReplicaConstraints struct {
+	sortedConstraintSet
+	replicaCount int32
+}
+
+var _ ReplicaConstraints = &sortedReplicaConstraints{}
+
+// ReplicaCount is part of the ReplicaConstraints interface.
+func (c *sortedReplicaConstraints) ReplicaCount() int32 {
+	return c.replicaCount
+}
@@ -0,0 +1,220 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ZoneCompare imposes a total ordering on zones, returning -1 if a sorts before
+// b, 1 if a sorts after b, and 0 if the zones are equivalent. Only information
+// exposed by the Zone interface is compared. Zones are ordered first by replica
+// count, then lexicographically by their replica constraint sets, and finally
+// lexicographically by their lease preferences.
+func ZoneCompare(a, b Zone) int {
+	if a.ReplicaCount() != b.ReplicaCount() {
+		return compareInt(int(a.ReplicaCount()), int(b.ReplicaCount()))
+	}
+	if cmp := compareZoneReplicaConstraints(a, b); cmp != 0 {
+		return cmp
+	}
+	return compareZoneLeasePreferences(a, b)
+}
+
+// ZoneChange describes a difference between two zones in one of the fields
+// exposed by the Zone interface.
+type ZoneChange struct {
+	// Field is the name of the zone config field, like num_replicas.
+	Field string
+	// Before and After are the formatted values of the field in the original
+	// and the changed zone.
+	Before, After string
+}
+
+// ZoneDiff returns the changes to the replica count, replica constraints, and
+// lease preferences, in that order, that turn the before zone into the after
+// zone. Zones are compared in the canonical form returned by NormalizeZone, so
+// reordering constraints is not a change; the changed fields are formatted as
+// they were entered. It returns nil if ZoneCompare considers the normalized
+// zones equivalent.
+func ZoneDiff(before, after Zone) []ZoneChange {
+	var changes []ZoneChange
+	normBefore, normAfter := NormalizeZone(before), NormalizeZone(after)
+	if before.ReplicaCount() != after.ReplicaCount() {
+		changes = append(changes, ZoneChange{
+			Field:  "num_replicas",
+			Before: fmt.Sprint(before.ReplicaCount()),
+			After:  fmt.Sprint(after.ReplicaCount()),
+		})
+	}
+	if compareZoneReplicaConstraints(normBefore, normAfter) != 0 {
+		changes = append(changes, ZoneChange{
+			Field:  "constraints",
+			Before: ZoneConstraintSummary(before),
+			After:  ZoneConstraintSummary(after),
+		})
+	}
+	if compareZoneLeasePreferences(normBefore, normAfter) != 0 {
+		changes = append(changes, ZoneChange{
+			Field:  "lease_preferences",
+			Before: formatZoneLeasePreferences(before),
+			After:  formatZoneLeasePreferences(after),
+		})
+	}
+	return changes
+}
+
+// ZoneSubsumes returns true if every node that satisfies the first replica
+// constraint set of zone also satisfies the first replica constraint set of
+// other, which is the case when zone's set is a superset of other's. A zone
+// without replica constraints is treated as having an empty set, so any zone
+// subsumes it.
+func ZoneSubsumes(zone, other Zone) bool {
+	if other.ReplicaConstraintsCount() == 0 {
+		return true
+	}
+	if zone.ReplicaConstraintsCount() == 0 {
+		return other.ReplicaConstraints(0).ConstraintCount() == 0
+	}
+	return ConstraintsSuperset(zone.ReplicaConstraints(0), other.ReplicaConstraints(0))
+}
+
+// supersetHashThreshold is the number of constraints in the smaller set above
+// which ConstraintsSuperset builds a hash set rather than comparing every pair
+// of constraints.
+const supersetHashThreshold = 8
+
+// ConstraintsSuperset returns true if every constraint in other is also in set.
+// Two constraints are the same if they have the same key, value, and type.
+// Replica counts are not compared.
+func ConstraintsSuperset(set, other ConstraintSet) bool {
+	setCount, otherCount := set.ConstraintCount(), other.ConstraintCount()
+	if otherCount <= supersetHashThreshold {
+		for i := 0; i < otherCount; i++ {
+			found := false
+			for j := 0; j < setCount; j++ {
+				if compareConstraints(other.Constraint(i), set.Constraint(j)) == 0 {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	type constraintKey struct {
+		key, value string
+		typ        int
+	}
+	makeKey := func(c Constraint) constraintKey {
+		return constraintKey{key: c.GetKey(), value: c.GetValue(), typ: constraintTypeOrder(c)}
+	}
+	seen := make(map[constraintKey]struct{}, setCount)
+	for i := 0; i < setCount; i++ {
+		seen[makeKey(set.Constraint(i))] = struct{}{}
+	}
+	for i := 0; i < otherCount; i++ {
+		if _, ok := seen[makeKey(other.Constraint(i))]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// compareZoneReplicaConstraints orders zones lexicographically by their replica
+// constraint sets.
+func compareZoneReplicaConstraints(a, b Zone) int {
+	aCount, bCount := a.ReplicaConstraintsCount(), b.ReplicaConstraintsCount()
+	for i := 0; i < aCount && i < bCount; i++ {
+		if cmp := compareReplicaConstraints(a.ReplicaConstraints(i), b.ReplicaConstraints(i)); cmp != 0 {
+			return cmp
+		}
+	}
+	return compareInt(aCount, bCount)
+}
+
+// compareZoneLeasePreferences orders zones lexicographically by their lease
+// preferences.
+func compareZoneLeasePreferences(a, b Zone) int {
+	aCount, bCount := a.LeasePreferenceCount(), b.LeasePreferenceCount()
+	for i := 0; i < aCount && i < bCount; i++ {
+		if cmp := compareConstraintSets(a.LeasePreference(i), b.LeasePreference(i)); cmp != 0 {
+			return cmp
+		}
+	}
+	return compareInt(aCount, bCount)
+}
+
+// compareReplicaConstraints orders replica constraint sets first by replica
+// count and then by their constraints.
+func compareReplicaConstraints(a, b ReplicaConstraints) int {
+	if a.ReplicaCount() != b.ReplicaCount() {
+		return compareInt(int(a.ReplicaCount()), int(b.ReplicaCount()))
+	}
+	return compareConstraintSets(a, b)
+}
+
+// compareConstraintSets orders constraint sets lexicographically by their
+// constraints. If one set is a prefix of the other, then the shorter set sorts
+// first.
+func compareConstraintSets(a, b ConstraintSet) int {
+	aCount, bCount := a.ConstraintCount(), b.ConstraintCount()
+	for i := 0; i < aCount && i < bCount; i++ {
+		if cmp := compareConstraints(a.Constraint(i), b.Constraint(i)); cmp != 0 {
+			return cmp
+		}
+	}
+	return compareInt(aCount, bCount)
+}
+
+// compareConstraints orders constraints by key, then by value, and finally
+// places required constraints before prohibited constraints, and prohibited
+// constraints before deprecated positive constraints.
+func compareConstraints(a, b Constraint) int {
+	if cmp := strings.Compare(a.GetKey(), b.GetKey()); cmp != 0 {
+		return cmp
+	}
+	if cmp := strings.Compare(a.GetValue(), b.GetValue()); cmp != 0 {
+		return cmp
+	}
+	return compareInt(constraintTypeOrder(a), constraintTypeOrder(b))
+}
+
+// constraintTypeOrder returns 0 for required constraints, 1 for prohibited
+// constraints, and 2 for deprecated positive constraints.
+func constraintTypeOrder(c Constraint) int {
+	switch {
+	case c.IsRequired():
+		return 0
+	case c.IsProhibited():
+		return 1
+	default:
+		return 2
+	}
+}
+
+func compareInt(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,234 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestZoneCompare(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+	positive := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_DEPRECATED_POSITIVE, Key: key, Value: value}
+	}
+
+	// Zones are listed in ascending order.
+	zones := []*config.ZoneConfig{
+		{},
+		{LeasePreferences: []config.LeasePreference{
+			{Constraints: []config.Constraint{required("region", "east")}},
+		}},
+		{Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{required("region", "east")}},
+		}},
+		{Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{required("region", "east"), required("dc", "1")}},
+		}},
+		{Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{prohibited("region", "east")}},
+		}},
+		{Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{positive("region", "east")}},
+		}},
+		{Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{required("region", "west")}},
+		}},
+		{NumReplicas: proto.Int32(3)},
+		{
+			NumReplicas: proto.Int32(3),
+			Constraints: []config.Constraints{
+				{NumReplicas: 1, Constraints: []config.Constraint{required("region", "west")}},
+				{NumReplicas: 2, Constraints: []config.Constraint{required("region", "east")}},
+			},
+		},
+		{
+			NumReplicas: proto.Int32(3),
+			Constraints: []config.Constraints{
+				{NumReplicas: 2, Constraints: []config.Constraint{required("region", "east")}},
+				{NumReplicas: 1, Constraints: []config.Constraint{required("region", "west")}},
+			},
+		},
+		{
+			NumReplicas: proto.Int32(3),
+			Constraints: []config.Constraints{
+				{NumReplicas: 2, Constraints: []config.Constraint{required("region", "east")}},
+				{NumReplicas: 1, Constraints: []config.Constraint{required("region", "west")}},
+			},
+			LeasePreferences: []config.LeasePreference{
+				{Constraints: []config.Constraint{required("region", "east")}},
+			},
+		},
+		{NumReplicas: proto.Int32(5)},
+	}
+
+	for i := range zones {
+		for j := range zones {
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			if actual := cat.ZoneCompare(zones[i], zones[j]); actual != expected {
+				t.Errorf("zone %d vs. zone %d: expected %d, got %d", i, j, expected, actual)
+			}
+		}
+	}
+}
+
+func TestZoneDiff(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+
+	east := &config.ZoneConfig{
+		NumReplicas: proto.Int32(3),
+		Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{required("region", "east"), prohibited("dc", "1")}},
+		},
+	}
+	perReplica := &config.ZoneConfig{
+		NumReplicas: proto.Int32(3),
+		Constraints: []config.Constraints{
+			{NumReplicas: 2, Constraints: []config.Constraint{required("region", "east")}},
+			{NumReplicas: 1, Constraints: []config.Constraint{required("region", "west")}},
+		},
+		LeasePreferences: []config.LeasePreference{
+			{Constraints: []config.Constraint{required("region", "east")}},
+			{Constraints: []config.Constraint{required("region", "west")}},
+		},
+	}
+
+	testCases := []struct {
+		before, after *config.ZoneConfig
+		expected      []cat.ZoneChange
+	}{
+		{before: &config.ZoneConfig{}, after: &config.ZoneConfig{}, expected: nil},
+		{before: east, after: east, expected: nil},
+		{
+			// Reordering constraints is not a change.
+			before: east,
+			after: &config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{
+					{Constraints: []config.Constraint{prohibited("dc", "1"), required("region", "east")}},
+				},
+			},
+			expected: nil,
+		},
+		{
+			before: &config.ZoneConfig{},
+			after:  east,
+			expected: []cat.ZoneChange{
+				{Field: "num_replicas", Before: "0", After: "3"},
+				{Field: "constraints", Before: "[]", After: "[+region=east,-dc=1]"},
+			},
+		},
+		{
+			before: east,
+			after:  perReplica,
+			expected: []cat.ZoneChange{
+				{
+					Field:  "constraints",
+					Before: "[+region=east,-dc=1]",
+					After:  "{+region=east: 2, +region=west: 1}",
+				},
+				{Field: "lease_preferences", Before: "[]", After: "[[+region=east], [+region=west]]"},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		actual := cat.ZoneDiff(tc.before, tc.after)
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%d: expected %+v, got %+v", i, tc.expected, actual)
+		}
+	}
+}
+
+func TestZoneSubsumes(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+	zone := func(constraints ...config.Constraint) *config.ZoneConfig {
+		return &config.ZoneConfig{Constraints: []config.Constraints{{Constraints: constraints}}}
+	}
+
+	// Build constraint sets large enough to use the hash set comparison.
+	var many, manyMinusOne []config.Constraint
+	for i := 0; i < 20; i++ {
+		c := required("dc", fmt.Sprint(i))
+		many = append(many, c)
+		if i != 7 {
+			manyMinusOne = append(manyMinusOne, c)
+		}
+	}
+
+	testCases := []struct {
+		zone, other *config.ZoneConfig
+		expected    bool
+	}{
+		{zone: &config.ZoneConfig{}, other: &config.ZoneConfig{}, expected: true},
+		{zone: zone(required("region", "east")), other: &config.ZoneConfig{}, expected: true},
+		{zone: &config.ZoneConfig{}, other: zone(required("region", "east")), expected: false},
+		{zone: &config.ZoneConfig{}, other: zone(), expected: true},
+		{
+			zone:     zone(required("region", "east"), prohibited("dc", "1")),
+			other:    zone(required("region", "east")),
+			expected: true,
+		},
+		{
+			zone:     zone(required("region", "east")),
+			other:    zone(required("region", "east"), prohibited("dc", "1")),
+			expected: false,
+		},
+		{zone: zone(required("dc", "1")), other: zone(prohibited("dc", "1")), expected: false},
+		{
+			zone:     zone(prohibited("dc", "1"), required("region", "east")),
+			other:    zone(required("region", "east"), prohibited("dc", "1")),
+			expected: true,
+		},
+		{zone: zone(many...), other: zone(manyMinusOne...), expected: true},
+		{zone: zone(manyMinusOne...), other: zone(many...), expected: false},
+		{
+			zone:     zone(append(manyMinusOne, required("dc", "x"))...),
+			other:    zone(many...),
+			expected: false,
+		},
+	}
+
+	for i, tc := range testCases {
+		if actual := cat.ZoneSubsumes(tc.zone, tc.other); actual != tc.expected {
+			t.Errorf("%d: expected %t, got %t", i, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import "sort"
+
+// ZoneConstraintConflict describes a key/value pair that one zone requires and
+// another zone prohibits. Zones are identified by the order in which they were
+// added to the ZoneConstraintConflictDetector.
+type ZoneConstraintConflict struct {
+	// RequiredZone is the index of the zone with the required constraint.
+	RequiredZone int
+
+	// ProhibitedZone is the index of the zone with the prohibited constraint.
+	ProhibitedZone int
+
+	// Required is the required constraint. The prohibited constraint has the
+	// same key and value.
+	Required Constraint
+}
+
+// ZoneConstraintConflictDetector finds conflicting replica constraints across a
+// collection of zones, such as the zones of every table and index in a
+// multi-region database. Two zones conflict if one of them requires a key/value
+// pair that the other prohibits, like +region=east and -region=east.
+// Constraints within a single zone are not checked against each other; use
+// ValidateZone for that.
+type ZoneConstraintConflictDetector struct {
+	zones []Zone
+}
+
+// Add adds the given zone to the set of zones that will be checked.
+func (d *ZoneConstraintConflictDetector) Add(zone Zone) {
+	d.zones = append(d.zones, zone)
+}
+
+// Conflicts returns every conflict between the zones added so far, ordered by
+// RequiredZone, then by ProhibitedZone, then by the position of the required
+// constraint in its zone. Each key/value pair is reported at most once for
+// each pair of zones.
+func (d *ZoneConstraintConflictDetector) Conflicts() []ZoneConstraintConflict {
+	type keyValue struct {
+		key, value string
+	}
+
+	// Index the zones that prohibit each key/value pair, in order of zone.
+	prohibitedBy := make(map[keyValue][]int)
+	for i, zone := range d.zones {
+		for j, n := 0, zone.ReplicaConstraintsCount(); j < n; j++ {
+			set := zone.ReplicaConstraints(j)
+			for k, m := 0, set.ConstraintCount(); k < m; k++ {
+				c := set.Constraint(k)
+				if c.IsRequired() {
+					continue
+				}
+				kv := keyValue{key: c.GetKey(), value: c.GetValue()}
+				if zones := prohibitedBy[kv]; len(zones) == 0 || zones[len(zones)-1] != i {
+					prohibitedBy[kv] = append(zones, i)
+				}
+			}
+		}
+	}
+	if len(prohibitedBy) == 0 {
+		return nil
+	}
+
+	var conflicts []ZoneConstraintConflict
+	for i, zone := range d.zones {
+		start := len(conflicts)
+		seen := make(map[keyValue]bool)
+		for j, n := 0, zone.ReplicaConstraintsCount(); j < n; j++ {
+			set := zone.ReplicaConstraints(j)
+			for k, m := 0, set.ConstraintCount(); k < m; k++ {
+				c := set.Constraint(k)
+				if !c.IsRequired() {
+					continue
+				}
+				kv := keyValue{key: c.GetKey(), value: c.GetValue()}
+				if seen[kv] {
+					continue
+				}
+				seen[kv] = true
+				for _, other := range prohibitedBy[kv] {
+					if other != i {
+						conflicts = append(conflicts, ZoneConstraintConflict{
+							RequiredZone:   i,
+							ProhibitedZone: other,
+							Required:       c,
+						})
+					}
+				}
+			}
+		}
+
+		// Order the conflicts of this zone by the prohibiting zone, keeping
+		// conflicts with the same prohibiting zone in constraint order.
+		zoneConflicts := conflicts[start:]
+		sort.SliceStable(zoneConflicts, func(j, k int) bool {
+			return zoneConflicts[j].ProhibitedZone < zoneConflicts[k].ProhibitedZone
+		})
+	}
+	return conflicts
+}
+
+// Reset removes all zones from the detector so that it can be reused.
+func (d *ZoneConstraintConflictDetector) Reset() {
+	d.zones = d.zones[:0]
+}
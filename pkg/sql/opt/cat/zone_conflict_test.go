@@ -0,0 +1,68 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestZoneConstraintConflictDetector(t *testing.T) {
+	zones := []string{
+		`[+region=east,-dc=1]`,
+		`[+region=west]`,
+		`{"+region=east,+dc=1": 2, "+dc=1,-region=west": 1}`,
+		`[]`,
+		`[-region=east,-region=west]`,
+	}
+
+	var d cat.ZoneConstraintConflictDetector
+	for _, constraints := range zones {
+		var zone config.ZoneConfig
+		if constraints != "[]" {
+			var list config.ConstraintsList
+			if err := yaml.UnmarshalStrict([]byte(constraints), &list); err != nil {
+				t.Fatal(err)
+			}
+			zone.Constraints = list.Constraints
+		}
+		d.Add(&zone)
+	}
+
+	var actual []string
+	for _, c := range d.Conflicts() {
+		actual = append(actual, fmt.Sprintf("%d %s %d", c.RequiredZone, c.Required, c.ProhibitedZone))
+	}
+	expected := []string{
+		"0 +region=east 4",
+		"1 +region=west 2",
+		"1 +region=west 4",
+		"2 +dc=1 0",
+		"2 +region=east 4",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, actual)
+	}
+
+	d.Reset()
+	if conflicts := d.Conflicts(); len(conflicts) != 0 {
+		t.Errorf("expected no conflicts after reset, got %v", conflicts)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import "github.com/cockroachdb/cockroach/pkg/roachpb"
+
+// ZoneConstraintEvalContext bundles the information about where a statement
+// is being planned and how the cluster's data is placed that is needed to
+// evaluate zone constraints against the gateway. It is built once by the
+// caller and handed to the optimizer, so that every part of the optimizer
+// sees the same gateway locality and zones, however they were obtained.
+type ZoneConstraintEvalContext struct {
+	// GatewayLocality is the locality of the gateway node. If it has no tiers,
+	// then the gateway's location is not known, and zone constraints do not
+	// affect planning.
+	GatewayLocality roachpb.Locality
+
+	// ClusterDefaultZone is the zone that applies to objects with no zone of
+	// their own, or to none of whose parents a zone applies. It may be nil.
+	ClusterDefaultZone Zone
+
+	// ZoneConfigStore is the source of the zones of other objects in the
+	// cluster, beyond those of the indexes in the catalog. It may be nil.
+	ZoneConfigStore ZoneConfigStore
+}
+
+// HasGatewayLocality returns true if the locality of the gateway node is
+// known.
+func (c *ZoneConstraintEvalContext) HasGatewayLocality() bool {
+	return len(c.GatewayLocality.Tiers) != 0
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// ZoneConfigExplain returns a plain English explanation of where the zone
+// places replicas and leases, and of whether reads issued from the given
+// gateway locality are likely to be served locally. For example:
+//
+//   2 replicas required in region=us-east; 1 replica required in
+//   region=us-west; leases preferred in region=us-east; gateway is in
+//   region=us-east so reads will be local.
+//
+// Reads are served by the leaseholder, so they are only known to be local if
+// the gateway satisfies the zone's first lease preference. Constraints on node
+// attributes (constraints without a key) cannot be checked against a locality
+// and are ignored when matching the gateway. If reads are remote and all
+// replicas are required to be in a region other than the gateway's, that
+// region is named as where reads are served from. The gateway is not
+// mentioned if its locality is empty.
+func ZoneConfigExplain(zone Zone, gatewayLocality roachpb.Locality) string {
+	var parts []string
+
+	if zone.ReplicaConstraintsCount() == 0 {
+		parts = append(parts, "replicas may be placed on any node")
+	}
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		replConstraint := zone.ReplicaConstraints(i)
+		parts = append(parts, fmt.Sprintf("%s %s",
+			describeReplicaCount(replConstraint.ReplicaCount()),
+			explainConstraintSet(replConstraint, "required in", "prohibited in")))
+	}
+
+	for i, n := 0, zone.LeasePreferenceCount(); i < n; i++ {
+		prefix := "leases"
+		if i != 0 {
+			prefix = "otherwise leases"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s",
+			prefix, explainConstraintSet(zone.LeasePreference(i), "preferred in", "avoided in")))
+	}
+
+	if len(gatewayLocality.Tiers) != 0 {
+		var reads string
+		if zone.LeasePreferenceCount() != 0 {
+			if LeasePreferenceMatchesLocality(zone, gatewayLocality) {
+				reads = "so reads will be local"
+			} else {
+				reads = "so reads will be remote"
+			}
+		} else {
+			reads = "but leases are not pinned so reads may be remote"
+			if zone.ReplicaConstraintsCount() != 0 {
+				canHoldReplica := false
+				for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+					if LocalitySatisfiesConstraints(gatewayLocality, zone.ReplicaConstraints(i)) {
+						canHoldReplica = true
+						break
+					}
+				}
+				if !canHoldReplica {
+					reads = "so reads will be remote"
+				}
+			}
+		}
+		if reads == "so reads will be remote" && zone.ReplicaConstraintsCount() == 1 {
+			// All replicas, and so the leaseholder, are in the region required
+			// by the only replica constraint set. It is only worth naming if
+			// the gateway is in another region.
+			if region, ok := RegionValue(zone); ok && !gatewayInRegion(gatewayLocality, region) {
+				reads += fmt.Sprintf(", from %s=%s", LocalityKeyRegion, region)
+			}
+		}
+		parts = append(parts, fmt.Sprintf("gateway is in %s %s", gatewayLocality, reads))
+	}
+
+	return strings.Join(parts, "; ") + "."
+}
+
+// gatewayInRegion returns true if the locality has a region tier with the given
+// value.
+func gatewayInRegion(locality roachpb.Locality, region string) bool {
+	for _, tier := range locality.Tiers {
+		if tier.Key == LocalityKeyRegion {
+			return tier.Value == region
+		}
+	}
+	return false
+}
+
+// explainConstraintSet describes the constraints in the set, like "required in
+// region=east and prohibited in dc=1", where required and prohibited are the
+// phrases used to introduce required and prohibited constraints.
+func explainConstraintSet(set ConstraintSet, required, prohibited string) string {
+	var requiredValues, prohibitedValues []string
+	for i, n := 0, set.ConstraintCount(); i < n; i++ {
+		constraint := set.Constraint(i)
+		if constraint.IsRequired() {
+			requiredValues = append(requiredValues, formatConstraintValue(constraint))
+		} else {
+			prohibitedValues = append(prohibitedValues, formatConstraintValue(constraint))
+		}
+	}
+
+	var phrases []string
+	if len(requiredValues) != 0 {
+		phrases = append(phrases, required+" "+strings.Join(requiredValues, " and "))
+	}
+	if len(prohibitedValues) != 0 {
+		phrases = append(phrases, prohibited+" "+strings.Join(prohibitedValues, " and "))
+	}
+	if len(phrases) == 0 {
+		return "may be placed on any node"
+	}
+	return strings.Join(phrases, " and ")
+}
+
+// LocalitySatisfiesConstraints returns true if the locality matches none of
+// the prohibited constraints in the set and every one of its other constraints,
+// including deprecated positive constraints. Constraints without a key apply to node attributes rather than locality
+// tiers, so they are ignored.
+func LocalitySatisfiesConstraints(locality roachpb.Locality, set ConstraintSet) bool {
+	for i, n := 0, set.ConstraintCount(); i < n; i++ {
+		constraint := set.Constraint(i)
+		if constraint.GetKey() == "" {
+			continue
+		}
+		matches := false
+		for _, tier := range locality.Tiers {
+			if tier.Key == constraint.GetKey() && tier.Value == constraint.GetValue() {
+				matches = true
+				break
+			}
+		}
+		if matches == constraint.IsProhibited() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestZoneConfigExplain(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+	locality := func(s string) roachpb.Locality {
+		var l roachpb.Locality
+		if err := l.Set(s); err != nil {
+			t.Fatal(err)
+		}
+		return l
+	}
+
+	perReplica := &config.ZoneConfig{
+		NumReplicas: proto.Int32(3),
+		Constraints: []config.Constraints{
+			{NumReplicas: 2, Constraints: []config.Constraint{required("region", "us-east")}},
+			{NumReplicas: 1, Constraints: []config.Constraint{required("region", "us-west")}},
+		},
+		LeasePreferences: []config.LeasePreference{
+			{Constraints: []config.Constraint{required("region", "us-east")}},
+		},
+	}
+	allReplicas := &config.ZoneConfig{
+		Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{
+				required("region", "us-east"), prohibited("dc", "1"), prohibited("", "ssd"),
+			}},
+		},
+		LeasePreferences: []config.LeasePreference{
+			{Constraints: []config.Constraint{required("dc", "2")}},
+			{Constraints: []config.Constraint{required("dc", "3")}},
+		},
+	}
+
+	testCases := []struct {
+		zone     *config.ZoneConfig
+		gateway  roachpb.Locality
+		expected string
+	}{
+		{
+			zone:     &config.ZoneConfig{},
+			expected: "replicas may be placed on any node.",
+		},
+		{
+			zone:    &config.ZoneConfig{},
+			gateway: locality("region=us-east"),
+			expected: "replicas may be placed on any node; " +
+				"gateway is in region=us-east but leases are not pinned so reads may be remote.",
+		},
+		{
+			zone:    perReplica,
+			gateway: locality("region=us-east"),
+			expected: "2 replicas required in region=us-east; 1 replica required in region=us-west; " +
+				"leases preferred in region=us-east; gateway is in region=us-east so reads will be local.",
+		},
+		{
+			zone:    perReplica,
+			gateway: locality("region=us-west"),
+			expected: "2 replicas required in region=us-east; 1 replica required in region=us-west; " +
+				"leases preferred in region=us-east; gateway is in region=us-west so reads will be remote.",
+		},
+		{
+			zone:    allReplicas,
+			gateway: locality("region=us-east,dc=2"),
+			expected: "all replicas required in region=us-east and prohibited in dc=1 and ssd; " +
+				"leases preferred in dc=2; otherwise leases preferred in dc=3; " +
+				"gateway is in region=us-east,dc=2 so reads will be local.",
+		},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "us-east")}},
+			}},
+			gateway: locality("region=eu"),
+			expected: "all replicas required in region=us-east; " +
+				"gateway is in region=eu so reads will be remote, from region=us-east.",
+		},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "us-east"), required("dc", "1")}},
+			}},
+			gateway: locality("region=us-east,dc=2"),
+			expected: "all replicas required in region=us-east and dc=1; " +
+				"gateway is in region=us-east,dc=2 so reads will be remote.",
+		},
+	}
+
+	for _, tc := range testCases {
+		if actual := cat.ZoneConfigExplain(tc.zone, tc.gateway); actual != tc.expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, actual)
+		}
+	}
+}
+
+func TestLocalitySatisfiesConstraints(t *testing.T) {
+	constraint := func(typ config.Constraint_Type, key, value string) config.Constraint {
+		return config.Constraint{Type: typ, Key: key, Value: value}
+	}
+	required := config.Constraint_REQUIRED
+	prohibited := config.Constraint_PROHIBITED
+	positive := config.Constraint_DEPRECATED_POSITIVE
+
+	testCases := []struct {
+		constraints []config.Constraint
+		locality    string
+		expected    bool
+	}{
+		{nil, "region=us-east", true},
+		{[]config.Constraint{constraint(required, "region", "us-east")}, "region=us-east", true},
+		{[]config.Constraint{constraint(required, "region", "us-east")}, "region=us-west", false},
+		{[]config.Constraint{constraint(prohibited, "region", "us-east")}, "region=us-east", false},
+		{[]config.Constraint{constraint(prohibited, "region", "us-east")}, "region=us-west", true},
+		{[]config.Constraint{constraint(positive, "region", "us-east")}, "region=us-east", true},
+		{[]config.Constraint{constraint(positive, "region", "us-east")}, "region=us-west", false},
+		{[]config.Constraint{constraint(required, "", "ssd")}, "region=us-west", true},
+		{
+			[]config.Constraint{constraint(positive, "region", "us-east"), constraint(prohibited, "dc", "1")},
+			"region=us-east,dc=2", true,
+		},
+		{
+			[]config.Constraint{constraint(positive, "region", "us-east"), constraint(prohibited, "dc", "1")},
+			"region=us-east,dc=1", false,
+		},
+	}
+
+	for _, tc := range testCases {
+		var locality roachpb.Locality
+		if err := locality.Set(tc.locality); err != nil {
+			t.Fatal(err)
+		}
+		zone := &config.ZoneConfig{Constraints: []config.Constraints{{Constraints: tc.constraints}}}
+		if actual := cat.LocalitySatisfiesConstraints(locality, zone.ReplicaConstraints(0)); actual != tc.expected {
+			t.Errorf("%v in %s: expected %t, got %t", tc.constraints, tc.locality, tc.expected, actual)
+		}
+	}
+}
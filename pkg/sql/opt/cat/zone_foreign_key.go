@@ -0,0 +1,109 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// ForeignKeyZones returns the zones of the two sides of a foreign key: the zone
+// of the referencing index idx, which holds the foreign key reference fkRef,
+// and the zone of the index that fkRef references.
+func ForeignKeyZones(
+	ctx context.Context, catalog Catalog, idx Index, fkRef ForeignKeyReference,
+) (referencing, referenced Zone, _ error) {
+	ds, err := catalog.ResolveDataSourceByID(ctx, fkRef.TableID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tab, ok := ds.(Table)
+	if !ok {
+		return nil, nil, errors.Errorf("foreign key references %s, which is not a table", ds.Name())
+	}
+	for i, n := 0, tab.IndexCount(); i < n; i++ {
+		if fkIndex := tab.Index(i); fkIndex.ID() == fkRef.IndexID {
+			return idx.Zone(), fkIndex.Zone(), nil
+		}
+	}
+	return nil, nil, errors.Errorf("foreign key references missing index %d of table %s",
+		fkRef.IndexID, ds.Name())
+}
+
+// ZoneLocalityMismatch returns true if the two zones place replicas or leases
+// differently, meaning that rows in one zone may be in other localities than
+// the rows they relate to in the other. For example, a foreign key whose
+// referencing and referenced indexes are in mismatched zones may need to check
+// or cascade to rows in another region. Replica counts are not compared, and
+// neither is the order of the constraints within a set.
+func ZoneLocalityMismatch(a, b Zone) bool {
+	a, b = NormalizeZone(a), NormalizeZone(b)
+	return compareZoneReplicaConstraints(a, b) != 0 || compareZoneLeasePreferences(a, b) != 0
+}
+
+// DetectFKZoneOverlap returns how similar the replica constraints are of the
+// zones on the two sides of a foreign key, as computed by
+// ZoneConstraintOverlap. The foreign key is given as for ForeignKeyZones.
+func DetectFKZoneOverlap(
+	ctx context.Context, catalog Catalog, idx Index, fkRef ForeignKeyReference,
+) (overlap float64, err error) {
+	referencing, referenced, err := ForeignKeyZones(ctx, catalog, idx, fkRef)
+	if err != nil {
+		return 0, err
+	}
+	return ZoneConstraintOverlap(referencing, referenced), nil
+}
+
+// ZoneConstraintOverlap returns the Jaccard similarity of the replica
+// constraints of the two zones: the number of distinct constraints that the
+// zones have in common, divided by the number of distinct constraints in
+// either. Which constraint set a constraint belongs to, and how many replicas
+// the set applies to, are ignored. Two zones without replica constraints have
+// an overlap of 1.
+func ZoneConstraintOverlap(a, b Zone) float64 {
+	aConstraints, bConstraints := zoneConstraintStrings(a), zoneConstraintStrings(b)
+	union := len(aConstraints)
+	intersection := 0
+	for c := range bConstraints {
+		if _, ok := aConstraints[c]; ok {
+			intersection++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 1
+	}
+	return float64(intersection) / float64(union)
+}
+
+// zoneConstraintStrings returns the distinct replica constraints of the zone,
+// formatted like +region=east.
+func zoneConstraintStrings(zone Zone) map[string]struct{} {
+	res := make(map[string]struct{})
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		set := zone.ReplicaConstraints(i)
+		for j, m := 0, set.ConstraintCount(); j < m; j++ {
+			constraint := set.Constraint(j)
+			prefix := "-"
+			if constraint.IsRequired() {
+				prefix = "+"
+			}
+			res[prefix+formatConstraintValue(constraint)] = struct{}{}
+		}
+	}
+	return res
+}
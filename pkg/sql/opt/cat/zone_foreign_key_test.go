@@ -0,0 +1,146 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/gogo/protobuf/proto"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestForeignKeyZones(t *testing.T) {
+	catalog := testcat.New()
+	for _, ddl := range []string{
+		"CREATE TABLE parent (p INT PRIMARY KEY)",
+		"CREATE TABLE child (c INT PRIMARY KEY, p INT REFERENCES parent (p), INDEX child_p_idx (p))",
+		"ALTER INDEX parent@primary CONFIGURE ZONE USING constraints='[+region=east]'",
+		"ALTER INDEX child@child_p_idx CONFIGURE ZONE USING constraints='[+region=west]'",
+	} {
+		if _, err := catalog.ExecuteDDL(ddl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	child := catalog.Table(tree.NewUnqualifiedTableName("child"))
+	var idx cat.Index
+	var fkRef cat.ForeignKeyReference
+	for i, n := 0, child.IndexCount(); i < n; i++ {
+		if ref, ok := child.Index(i).ForeignKey(); ok {
+			idx, fkRef = child.Index(i), ref
+		}
+	}
+	if idx == nil {
+		t.Fatal("expected child to have a foreign key")
+	}
+
+	referencing, referenced, err := cat.ForeignKeyZones(context.Background(), catalog, idx, fkRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := func(zone cat.Zone, expected string) {
+		t.Helper()
+		if actual := cat.ZoneConstraintSummary(zone); actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	}
+	expect(referencing, "[+region=west]")
+	expect(referenced, "[+region=east]")
+	if !cat.ZoneLocalityMismatch(referencing, referenced) {
+		t.Error("expected zones of foreign key to mismatch")
+	}
+	overlap, err := cat.DetectFKZoneOverlap(context.Background(), catalog, idx, fkRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if overlap != 0 {
+		t.Errorf("expected zones of foreign key not to overlap, got %f", overlap)
+	}
+}
+
+func TestZoneLocalityMismatch(t *testing.T) {
+	makeZone := func(numReplicas int32, constraints, leasePreferences string) *config.ZoneConfig {
+		zone := &config.ZoneConfig{NumReplicas: proto.Int32(numReplicas)}
+		var constraintsList config.ConstraintsList
+		if err := yaml.UnmarshalStrict([]byte(constraints), &constraintsList); err != nil {
+			t.Fatal(err)
+		}
+		zone.Constraints = constraintsList.Constraints
+		if err := yaml.UnmarshalStrict([]byte(leasePreferences), &zone.LeasePreferences); err != nil {
+			t.Fatal(err)
+		}
+		return zone
+	}
+
+	testCases := []struct {
+		a, b     *config.ZoneConfig
+		expected bool
+	}{
+		{makeZone(3, "[]", "[]"), makeZone(3, "[]", "[]"), false},
+		{makeZone(3, "[]", "[]"), makeZone(5, "[]", "[]"), false},
+		{makeZone(3, "[+region=east,+dc=1]", "[]"), makeZone(3, "[+dc=1,+region=east]", "[]"), false},
+		{makeZone(3, "[+region=east]", "[]"), makeZone(3, "[+region=west]", "[]"), true},
+		{makeZone(3, "[+region=east]", "[]"), makeZone(3, "[]", "[]"), true},
+		{makeZone(3, "[]", "[[+region=east]]"), makeZone(3, "[]", "[[+region=west]]"), true},
+		{
+			makeZone(3, `{"+region=east": 2, "+region=west": 1}`, "[]"),
+			makeZone(3, `{"+region=west": 1, "+region=east": 2}`, "[]"),
+			false,
+		},
+	}
+
+	for i, tc := range testCases {
+		if actual := cat.ZoneLocalityMismatch(tc.a, tc.b); actual != tc.expected {
+			t.Errorf("%d: expected %t, got %t", i, tc.expected, actual)
+		}
+	}
+}
+
+func TestZoneConstraintOverlap(t *testing.T) {
+	makeZone := func(constraints string) *config.ZoneConfig {
+		var constraintsList config.ConstraintsList
+		if err := yaml.UnmarshalStrict([]byte(constraints), &constraintsList); err != nil {
+			t.Fatal(err)
+		}
+		return &config.ZoneConfig{Constraints: constraintsList.Constraints}
+	}
+
+	testCases := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"[]", "[]", 1},
+		{"[+region=east]", "[]", 0},
+		{"[+region=east]", "[+region=east]", 1},
+		{"[+region=east]", "[-region=east]", 0},
+		{"[+region=east,+dc=1]", "[+dc=1,+region=east]", 1},
+		{"[+region=east,+dc=1]", "[+region=east,+dc=2]", 1.0 / 3},
+		{`{"+region=east": 2, "+region=west": 1}`, "[+region=east]", 0.5},
+	}
+
+	for i, tc := range testCases {
+		if actual := cat.ZoneConstraintOverlap(makeZone(tc.a), makeZone(tc.b)); actual != tc.expected {
+			t.Errorf("%d: expected %f, got %f", i, tc.expected, actual)
+		}
+		if actual := cat.ZoneConstraintOverlap(makeZone(tc.b), makeZone(tc.a)); actual != tc.expected {
+			t.Errorf("%d: expected %f when reversed, got %f", i, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import (
+	"sort"
+	"strings"
+)
+
+// ZoneConstraintGraph groups tables by the placement of their data, so that
+// tables which are co-located can be found without comparing every pair of
+// zones. Two tables are co-located if the required constraints in the first
+// set of replica constraints of their zones are the same, ignoring order. A
+// table whose zone has no required constraints may be placed anywhere, so it is
+// not co-located with any other table.
+type ZoneConstraintGraph struct {
+	// groups maps from the canonical form of a set of required constraints to
+	// the tables that have that set, in the order they were added.
+	groups map[string][]StableID
+
+	// keys maps from each constrained table to the key of its group.
+	keys map[StableID]string
+}
+
+// AddTable adds a table with the given zone to the graph. Adding a table that
+// is already in the graph has no effect.
+func (g *ZoneConstraintGraph) AddTable(tableID StableID, zone Zone) {
+	if _, ok := g.keys[tableID]; ok {
+		return
+	}
+	key := zoneGraphKey(zone)
+	if key == "" {
+		return
+	}
+	if g.groups == nil {
+		g.groups = make(map[string][]StableID)
+		g.keys = make(map[StableID]string)
+	}
+	g.groups[key] = append(g.groups[key], tableID)
+	g.keys[tableID] = key
+}
+
+// ColocatedTables returns the other tables in the graph that are co-located
+// with the given table, in the order they were added. It returns nil if the
+// table is not in the graph or has no required constraints.
+func (g *ZoneConstraintGraph) ColocatedTables(tableID StableID) []StableID {
+	key, ok := g.keys[tableID]
+	if !ok {
+		return nil
+	}
+	var res []StableID
+	for _, id := range g.groups[key] {
+		if id != tableID {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// Colocated returns true if both tables are in the graph and are co-located.
+// A table is co-located with itself if its zone has required constraints.
+func (g *ZoneConstraintGraph) Colocated(left, right StableID) bool {
+	leftKey, ok := g.keys[left]
+	return ok && leftKey == g.keys[right]
+}
+
+// Constrained returns true if the table is in the graph and its zone has
+// required constraints.
+func (g *ZoneConstraintGraph) Constrained(tableID StableID) bool {
+	_, ok := g.keys[tableID]
+	return ok
+}
+
+// Empty returns true if no table in the graph has required constraints.
+func (g *ZoneConstraintGraph) Empty() bool {
+	return len(g.keys) == 0
+}
+
+// zoneGraphKey returns the canonical form of the required constraints in the
+// first set of replica constraints of the zone, or the empty string if there
+// are none.
+func zoneGraphKey(zone Zone) string {
+	if zone == nil || zone.ReplicaConstraintsCount() == 0 {
+		return ""
+	}
+	set := zone.ReplicaConstraints(0)
+	var required []string
+	for i, n := 0, set.ConstraintCount(); i < n; i++ {
+		c := set.Constraint(i)
+		if c.IsRequired() {
+			// Use NUL separators so that, say, a=b,c and a,b=c do not collide.
+			required = append(required, c.GetKey()+"\x00"+c.GetValue())
+		}
+	}
+	sort.Strings(required)
+	return strings.Join(required, "\x00\x00")
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestZoneConstraintGraph(t *testing.T) {
+	tables := []struct {
+		id          cat.StableID
+		constraints string
+	}{
+		{id: 1, constraints: `[+region=east,+dc=1]`},
+		{id: 2, constraints: `[+dc=1,-ssd,+region=east]`},
+		{id: 3, constraints: `[+region=east]`},
+		{id: 4, constraints: `{"+region=east,+dc=1": 2, "+region=west": 1}`},
+		{id: 5, constraints: `[-region=west]`},
+		{id: 6, constraints: `[]`},
+		{id: 7, constraints: `[+region=east]`},
+	}
+
+	var g cat.ZoneConstraintGraph
+	if !g.Empty() {
+		t.Errorf("expected empty graph")
+	}
+	for _, tab := range tables {
+		var zone config.ZoneConfig
+		if tab.constraints != "[]" {
+			var list config.ConstraintsList
+			if err := yaml.UnmarshalStrict([]byte(tab.constraints), &list); err != nil {
+				t.Fatal(err)
+			}
+			zone.Constraints = list.Constraints
+		}
+		g.AddTable(tab.id, &zone)
+	}
+
+	expected := map[cat.StableID][]cat.StableID{
+		1: {2, 4},
+		2: {1, 4},
+		3: {7},
+		4: {1, 2},
+		5: nil,
+		6: nil,
+		7: {3},
+		8: nil,
+	}
+	for id, exp := range expected {
+		if actual := g.ColocatedTables(id); !reflect.DeepEqual(actual, exp) {
+			t.Errorf("%d: expected %v, got %v", id, exp, actual)
+		}
+	}
+
+	if !g.Colocated(1, 1) || !g.Colocated(1, 4) || g.Colocated(1, 3) || g.Colocated(6, 6) {
+		t.Errorf("unexpected co-location")
+	}
+	if !g.Constrained(1) || g.Constrained(5) || g.Constrained(6) || g.Constrained(8) {
+		t.Errorf("unexpected constrained tables")
+	}
+	if g.Empty() {
+		t.Errorf("expected non-empty graph")
+	}
+}
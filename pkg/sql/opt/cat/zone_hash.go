@@ -0,0 +1,95 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"sort"
+)
+
+// ZoneHasher computes a stable hash over a collection of zones, such as the
+// zones of every index scanned by a query. Only information exposed by the
+// Zone interface contributes to the hash, and zones are hashed in the canonical
+// form returned by NormalizeZone, so zones that ZoneCompare considers
+// equivalent once normalized hash identically. The hash does not depend on the
+// order in which zones are added, since they are sorted using ZoneCompare
+// before hashing.
+type ZoneHasher struct {
+	zones []Zone
+	hash  hash.Hash64
+	buf   [binary.MaxVarintLen64]byte
+}
+
+// Add adds the given zone to the set of zones that will be hashed.
+func (h *ZoneHasher) Add(zone Zone) {
+	h.zones = append(h.zones, NormalizeZone(zone))
+}
+
+// Sum64 returns the hash of all zones added so far.
+func (h *ZoneHasher) Sum64() uint64 {
+	sort.Slice(h.zones, func(i, j int) bool {
+		return ZoneCompare(h.zones[i], h.zones[j]) < 0
+	})
+
+	if h.hash == nil {
+		h.hash = fnv.New64a()
+	} else {
+		h.hash.Reset()
+	}
+	for _, zone := range h.zones {
+		h.writeInt(int64(zone.ReplicaCount()))
+		h.writeInt(int64(zone.ReplicaConstraintsCount()))
+		for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+			replConstraint := zone.ReplicaConstraints(i)
+			h.writeInt(int64(replConstraint.ReplicaCount()))
+			h.writeConstraintSet(replConstraint)
+		}
+		h.writeInt(int64(zone.LeasePreferenceCount()))
+		for i, n := 0, zone.LeasePreferenceCount(); i < n; i++ {
+			h.writeConstraintSet(zone.LeasePreference(i))
+		}
+	}
+	return h.hash.Sum64()
+}
+
+// Reset removes all zones from the hasher so that it can be reused.
+func (h *ZoneHasher) Reset() {
+	h.zones = h.zones[:0]
+}
+
+func (h *ZoneHasher) writeConstraintSet(set ConstraintSet) {
+	h.writeInt(int64(set.ConstraintCount()))
+	for i, n := 0, set.ConstraintCount(); i < n; i++ {
+		constraint := set.Constraint(i)
+		h.writeInt(int64(constraintTypeOrder(constraint)))
+		h.writeString(constraint.GetKey())
+		h.writeString(constraint.GetValue())
+	}
+}
+
+func (h *ZoneHasher) writeInt(val int64) {
+	n := binary.PutVarint(h.buf[:], val)
+	// Writes to a hash.Hash never return an error.
+	_, _ = h.hash.Write(h.buf[:n])
+}
+
+func (h *ZoneHasher) writeString(val string) {
+	// Prefix strings with their length so that adjacent strings can't be
+	// confused with one another (e.g. "ab" + "c" vs. "a" + "bc").
+	h.writeInt(int64(len(val)))
+	_, _ = h.hash.Write([]byte(val))
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/gogo/protobuf/proto"
+)
+
+func makeHashTestZone(region string, numReplicas int32) *config.ZoneConfig {
+	return &config.ZoneConfig{
+		NumReplicas: proto.Int32(numReplicas),
+		Constraints: []config.Constraints{{Constraints: []config.Constraint{
+			{Type: config.Constraint_REQUIRED, Key: "region", Value: region},
+		}}},
+		LeasePreferences: []config.LeasePreference{{Constraints: []config.Constraint{
+			{Type: config.Constraint_REQUIRED, Key: "region", Value: region},
+		}}},
+	}
+}
+
+func TestZoneHasher(t *testing.T) {
+	hash := func(zones ...cat.Zone) uint64 {
+		var h cat.ZoneHasher
+		for _, zone := range zones {
+			h.Add(zone)
+		}
+		return h.Sum64()
+	}
+
+	east := makeHashTestZone("east", 3)
+	west := makeHashTestZone("west", 3)
+
+	// Equivalent zones hash identically.
+	if hash(east) != hash(makeHashTestZone("east", 3)) {
+		t.Errorf("expected equivalent zones to hash identically")
+	}
+
+	// The order in which zones are added doesn't matter.
+	if hash(east, west) != hash(west, east) {
+		t.Errorf("expected hash to be independent of zone order")
+	}
+
+	// Neither does the order of the constraints within a zone.
+	dcEast := makeHashTestZone("east", 3)
+	dcEast.Constraints[0].Constraints = append([]config.Constraint{
+		{Type: config.Constraint_REQUIRED, Key: "dc", Value: "1"},
+	}, dcEast.Constraints[0].Constraints...)
+	eastDC := makeHashTestZone("east", 3)
+	eastDC.Constraints[0].Constraints = append(eastDC.Constraints[0].Constraints,
+		config.Constraint{Type: config.Constraint_REQUIRED, Key: "dc", Value: "1"})
+	if hash(dcEast) != hash(eastDC) {
+		t.Errorf("expected hash to be independent of constraint order")
+	}
+
+	// Differences in any field change the hash.
+	distinct := []uint64{
+		hash(),
+		hash(&config.ZoneConfig{}),
+		hash(east),
+		hash(west),
+		hash(east, west),
+		hash(makeHashTestZone("east", 5)),
+		hash(makeHashTestZone("eas", 3)),
+		hash(&config.ZoneConfig{Constraints: east.Constraints}),
+		hash(&config.ZoneConfig{LeasePreferences: east.LeasePreferences}),
+		hash(&config.ZoneConfig{Constraints: []config.Constraints{{Constraints: []config.Constraint{
+			{Type: config.Constraint_PROHIBITED, Key: "region", Value: "east"},
+		}}}}),
+	}
+	for i := range distinct {
+		for j := i + 1; j < len(distinct); j++ {
+			if distinct[i] == distinct[j] {
+				t.Errorf("expected hashes %d and %d to differ", i, j)
+			}
+		}
+	}
+
+	// A hasher can be reused after Reset.
+	var h cat.ZoneHasher
+	h.Add(west)
+	h.Sum64()
+	h.Reset()
+	h.Add(east)
+	if h.Sum64() != hash(east) {
+		t.Errorf("expected reset hasher to only hash newly added zones")
+	}
+}
+
+func BenchmarkZoneHasher(b *testing.B) {
+	zones := make([]cat.Zone, 10)
+	for i := range zones {
+		zones[i] = makeHashTestZone(fmt.Sprintf("region%d", i), 3)
+	}
+
+	var h cat.ZoneHasher
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Reset()
+		for _, zone := range zones {
+			h.Add(zone)
+		}
+		h.Sum64()
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+// ZoneLevel is one level of the hierarchy of zones that an object inherits its
+// zone from, such as the cluster default zone or the zone of the object's
+// database. A list of levels ordered from outermost to innermost is called a
+// zone inheritance chain; each field of the object's zone is taken from the
+// innermost level that sets it.
+type ZoneLevel struct {
+	// Level is the kind of object at this level: "cluster", "range",
+	// "database", "table", "index" or "partition".
+	Level string
+
+	// Name identifies the object at this level using the syntax of the zone
+	// command line tools, like "db.t@idx".
+	Name string
+
+	// Zone is the zone stored at this level, or nil if none is. Fields that
+	// are not set at this level are inherited from the level above.
+	Zone Zone
+
+	// SetFields lists the names of the zone fields that are set at this level,
+	// using the names accepted by CONFIGURE ZONE, like "num_replicas".
+	SetFields []string
+}
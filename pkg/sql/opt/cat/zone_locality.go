@@ -0,0 +1,162 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import "github.com/cockroachdb/cockroach/pkg/roachpb"
+
+// Well-known locality tier keys. Constraints on these keys restrict where in
+// the world replicas are placed, whereas constraints on other keys, like
+// +ssd=true, usually restrict the hardware that replicas are placed on.
+const (
+	LocalityKeyRegion     = "region"
+	LocalityKeyZone       = "zone"
+	LocalityKeyDatacenter = "datacenter"
+	LocalityKeyRack       = "rack"
+)
+
+// IsNodeLocality returns true if the constraint's key is one of the well-known
+// locality tier keys, meaning that it constrains the geographic placement of
+// replicas rather than the attributes of the nodes they are placed on. The
+// optimizer uses this to separate constraints that affect the cost of crossing
+// regions from constraints that only affect which nodes within a region are
+// chosen.
+func IsNodeLocality(c Constraint) bool {
+	switch c.GetKey() {
+	case LocalityKeyRegion, LocalityKeyZone, LocalityKeyDatacenter, LocalityKeyRack:
+		return true
+	}
+	return false
+}
+
+// LocalityScore returns a number from 0.0 to 1.0 that describes how well
+// the given locality matches the given zone constraints, with 0.0
+// indicating 0% and 1.0 indicating 100%. In order to match, each successive
+// locality tier must match at least one REQUIRED constraint and not match any
+// PROHIBITED constraints. Locality tiers are hierarchical, so if a locality
+// tier does not match, then tiers after it do not match either. For example:
+//
+//   Locality = [region=us,dc=east]
+//   0.0      = []
+//   0.0      = [+region=eu,+dc=uk]
+//   0.0      = [-region=us]
+//   0.0      = [+region=eu,+dc=east]
+//   0.5      = [+region=us,+dc=west]
+//   0.5      = [+region=us,-dc=east]
+//   1.0      = [+region=us,+dc=east]
+//   1.0      = [+region=us,+dc=east,+rack=1,-ssd]
+//
+// Note that constraints need not be specified in any particular order, so scan
+// all constraints when matching each locality tier. An empty locality does not
+// match any zone.
+func LocalityScore(zone Zone, locality roachpb.Locality) float64 {
+	// If there are no replica constraints, then locality can't match.
+	if zone.ReplicaConstraintsCount() == 0 || len(locality.Tiers) == 0 {
+		return 0.0
+	}
+
+	// matchTier returns true if it can locate a required constraint that matches
+	// the given tier.
+	matchConstraints := func(zc ReplicaConstraints, tier *roachpb.Tier) bool {
+		for i, n := 0, zc.ConstraintCount(); i < n; i++ {
+			con := zc.Constraint(i)
+			if tier.Key == con.GetKey() && tier.Value == con.GetValue() {
+				// If this is a required constraint, then it matches, and no need to
+				// iterate further. If it's prohibited, then it cannot match, so no
+				// need to go further.
+				return con.IsRequired()
+			}
+		}
+		return false
+	}
+
+	// matchReplConstraints returns true if all replica constraints match the
+	// given tier.
+	matchReplConstraints := func(zone Zone, tier *roachpb.Tier) bool {
+		for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+			replCon := zone.ReplicaConstraints(i)
+			if !matchConstraints(replCon, tier) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Keep iterating until non-matching tier is found, or all tiers are found to
+	// match.
+	matchCount := 0
+	for i := range locality.Tiers {
+		if !matchReplConstraints(zone, &locality.Tiers[i]) {
+			break
+		}
+		matchCount++
+	}
+
+	return float64(matchCount) / float64(len(locality.Tiers))
+}
+
+// RegionValue returns the value of the first required constraint on the
+// region locality tier in the replica constraints of the zone, searching the
+// replica constraint sets in order. It returns ok=false if no replica
+// constraint requires a region.
+func RegionValue(zone Zone) (region string, ok bool) {
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		replConstraint := zone.ReplicaConstraints(i)
+		for j, m := 0, replConstraint.ConstraintCount(); j < m; j++ {
+			constraint := replConstraint.Constraint(j)
+			if constraint.IsRequired() && constraint.GetKey() == LocalityKeyRegion {
+				return constraint.GetValue(), true
+			}
+		}
+	}
+	return "", false
+}
+
+// LeasePreferenceMatchesLocality returns true if the zone has lease
+// preferences and the locality satisfies the first, most preferred, of them.
+// This is the common case in which the leaseholders of the zone's ranges, and
+// so the replicas that serve reads, are expected to be in the locality.
+func LeasePreferenceMatchesLocality(zone Zone, locality roachpb.Locality) bool {
+	return zone.LeasePreferenceCount() > 0 &&
+		LocalitySatisfiesConstraints(locality, zone.LeasePreference(0))
+}
+
+// IsSatisfiable returns true if nodes with the given localities can hold the
+// replicas of the zone without violating its replica constraints. A replica
+// constraint set that applies to a number of replicas must be satisfied by at
+// least that many of the localities, while a set that applies to all replicas
+// must be satisfied by at least one. Constraints on node attributes are
+// ignored, as are the lease preferences of the zone, which are not required to
+// be satisfiable.
+func IsSatisfiable(zone Zone, localities []roachpb.Locality) bool {
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		replConstraint := zone.ReplicaConstraints(i)
+		needed := int(replConstraint.ReplicaCount())
+		if needed == 0 {
+			needed = 1
+		}
+		for j := range localities {
+			if LocalitySatisfiesConstraints(localities[j], replConstraint) {
+				needed--
+				if needed == 0 {
+					break
+				}
+			}
+		}
+		if needed > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestLocalityScore(t *testing.T) {
+	locality := roachpb.Locality{Tiers: []roachpb.Tier{
+		{Key: "region", Value: "us"},
+		{Key: "dc", Value: "east"},
+	}}
+
+	testCases := []struct {
+		constraints string
+		expected    float64
+	}{
+		{constraints: "[]", expected: 0},
+		{constraints: "[+region=eu,+dc=uk]", expected: 0},
+		{constraints: "[-region=us]", expected: 0},
+		{constraints: "[+region=eu,+dc=east]", expected: 0},
+		{constraints: "[+region=us,+dc=west]", expected: 0.5},
+		{constraints: "[+region=us,-dc=east]", expected: 0.5},
+		{constraints: "[+region=us,+dc=east]", expected: 1},
+		{constraints: "[+region=us,+dc=east,+rack=1,-ssd]", expected: 1},
+		{constraints: `{"+region=us,+dc=east": 2, "+region=us,+dc=west": 1}`, expected: 0.5},
+	}
+
+	for _, tc := range testCases {
+		var zone config.ZoneConfig
+		if tc.constraints != "[]" {
+			var constraints config.ConstraintsList
+			if err := yaml.UnmarshalStrict([]byte(tc.constraints), &constraints); err != nil {
+				t.Fatal(err)
+			}
+			zone.Constraints = constraints.Constraints
+		}
+		if actual := cat.LocalityScore(&zone, locality); actual != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.constraints, tc.expected, actual)
+		}
+	}
+
+	if actual := cat.LocalityScore(&config.ZoneConfig{}, roachpb.Locality{}); actual != 0 {
+		t.Errorf("empty locality: expected 0, got %v", actual)
+	}
+}
+
+func TestIsNodeLocality(t *testing.T) {
+	testCases := []struct {
+		constraint config.Constraint
+		expected   bool
+	}{
+		{constraint: config.Constraint{Type: config.Constraint_REQUIRED, Key: "region", Value: "us-east"}, expected: true},
+		{constraint: config.Constraint{Type: config.Constraint_PROHIBITED, Key: "zone", Value: "a"}, expected: true},
+		{constraint: config.Constraint{Type: config.Constraint_REQUIRED, Key: "datacenter", Value: "dc1"}, expected: true},
+		{constraint: config.Constraint{Type: config.Constraint_REQUIRED, Key: "rack", Value: "1"}, expected: true},
+		{constraint: config.Constraint{Type: config.Constraint_REQUIRED, Key: "ssd", Value: "true"}, expected: false},
+		{constraint: config.Constraint{Type: config.Constraint_REQUIRED, Value: "ssd"}, expected: false},
+		{constraint: config.Constraint{Type: config.Constraint_REQUIRED, Key: "Region", Value: "us-east"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		if actual := cat.IsNodeLocality(&tc.constraint); actual != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.constraint.String(), tc.expected, actual)
+		}
+	}
+}
+
+func TestRegionValue(t *testing.T) {
+	testCases := []struct {
+		constraints string
+		region      string
+		ok          bool
+	}{
+		{constraints: "[]", ok: false},
+		{constraints: "[+dc=east,-ssd]", ok: false},
+		{constraints: "[-region=us]", ok: false},
+		{constraints: "[+dc=east,+region=us]", region: "us", ok: true},
+		{constraints: "[-region=eu,+region=us]", region: "us", ok: true},
+		{constraints: `{"+dc=east": 2, "+region=us": 1}`, region: "us", ok: true},
+		{constraints: `{"+region=eu": 1, "+region=us": 2}`, region: "eu", ok: true},
+	}
+
+	for _, tc := range testCases {
+		var zone config.ZoneConfig
+		if tc.constraints != "[]" {
+			var constraints config.ConstraintsList
+			if err := yaml.UnmarshalStrict([]byte(tc.constraints), &constraints); err != nil {
+				t.Fatal(err)
+			}
+			zone.Constraints = constraints.Constraints
+		}
+		region, ok := cat.RegionValue(&zone)
+		if region != tc.region || ok != tc.ok {
+			t.Errorf("%s: expected %q, %v, got %q, %v", tc.constraints, tc.region, tc.ok, region, ok)
+		}
+	}
+}
+
+func TestLeasePreferenceMatchesLocality(t *testing.T) {
+	locality := roachpb.Locality{Tiers: []roachpb.Tier{
+		{Key: "region", Value: "us"},
+		{Key: "dc", Value: "east"},
+	}}
+
+	testCases := []struct {
+		leasePreferences string
+		expected         bool
+	}{
+		{leasePreferences: "[]", expected: false},
+		{leasePreferences: "[[+region=us]]", expected: true},
+		{leasePreferences: "[[+region=us,+dc=east]]", expected: true},
+		{leasePreferences: "[[+region=us,+dc=west]]", expected: false},
+		{leasePreferences: "[[-region=us]]", expected: false},
+		{leasePreferences: "[[+region=eu], [+region=us]]", expected: false},
+		{leasePreferences: "[[+region=us], [+region=eu]]", expected: true},
+	}
+
+	for _, tc := range testCases {
+		var zone config.ZoneConfig
+		if err := yaml.UnmarshalStrict([]byte(tc.leasePreferences), &zone.LeasePreferences); err != nil {
+			t.Fatal(err)
+		}
+		if actual := cat.LeasePreferenceMatchesLocality(&zone, locality); actual != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.leasePreferences, tc.expected, actual)
+		}
+	}
+}
+
+func TestIsSatisfiable(t *testing.T) {
+	makeLocality := func(region, dc string) roachpb.Locality {
+		return roachpb.Locality{Tiers: []roachpb.Tier{
+			{Key: "region", Value: region},
+			{Key: "dc", Value: dc},
+		}}
+	}
+	localities := []roachpb.Locality{
+		makeLocality("us", "east"),
+		makeLocality("us", "west"),
+		makeLocality("eu", "west"),
+	}
+
+	testCases := []struct {
+		constraints string
+		expected    bool
+	}{
+		{constraints: "[]", expected: true},
+		{constraints: "[+region=us]", expected: true},
+		{constraints: "[+region=us,-dc=east]", expected: true},
+		{constraints: "[+region=ap]", expected: false},
+		{constraints: "[+region=eu,+dc=east]", expected: false},
+		{constraints: "[-region=us,-region=eu]", expected: false},
+		// Constraints on node attributes are ignored.
+		{constraints: "[+ssd]", expected: true},
+		{constraints: `{"+region=us": 2, "+region=eu": 1}`, expected: true},
+		{constraints: `{"+region=us": 1, "+region=eu": 2}`, expected: false},
+		{constraints: `{"+region=us": 1, "+region=ap": 1}`, expected: false},
+	}
+
+	for _, tc := range testCases {
+		var zone config.ZoneConfig
+		if tc.constraints != "[]" {
+			var constraints config.ConstraintsList
+			if err := yaml.UnmarshalStrict([]byte(tc.constraints), &constraints); err != nil {
+				t.Fatal(err)
+			}
+			zone.Constraints = constraints.Constraints
+		}
+		if actual := cat.IsSatisfiable(&zone, localities); actual != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.constraints, tc.expected, actual)
+		}
+	}
+
+	// No zone with replica constraints can be satisfied without nodes.
+	var zone config.ZoneConfig
+	if err := yaml.UnmarshalStrict([]byte("constraints: [+ssd]"), &zone); err != nil {
+		t.Fatal(err)
+	}
+	if cat.IsSatisfiable(&zone, nil) {
+		t.Error("expected zone not to be satisfiable without nodes")
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import "context"
+
+// ZoneConfigStore is an interface to a source of zone configurations. It allows
+// code that consumes zones to be written without depending on where the zones
+// come from, so that callers can choose between reading them transactionally
+// from KV and reading them from a cache, and so that tests can supply zones
+// directly.
+type ZoneConfigStore interface {
+	// GetTableZone returns the zone that applies to the table with the given
+	// StableID. If the table has no zone of its own, the zone it inherits from
+	// its database or from the default zone is returned instead.
+	GetTableZone(ctx context.Context, tableID StableID) (Zone, error)
+
+	// ListAllZones returns every zone that has been explicitly configured, in
+	// order of the ID of the object the zone is attached to.
+	ListAllZones(ctx context.Context) ([]Zone, error)
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import "github.com/cockroachdb/cockroach/pkg/roachpb"
+
+// SuggestNodesToSatisfyZone returns the localities of nodes that, if added to
+// the cluster, would allow the replica constraints of the zone to be
+// satisfied, given the nodes that already exist. The list has one entry per
+// node to add, so a locality may appear several times. Each locality is made
+// of the required constraints of a set whose replicas lack matching nodes, in
+// the order they appear in the set, like "region=eu-west,zone=eu-west-1a".
+//
+// A set of constraints that applies to all replicas needs as many matching
+// nodes as the zone has replicas, or one if the zone inherits its replica
+// count. Sets with no required locality constraints are skipped, since no
+// locality can be derived from them; they can be satisfied by any node that
+// is not excluded. Existing nodes are counted toward the sets in order, so the
+// suggestions may not be minimal when the sets overlap.
+func SuggestNodesToSatisfyZone(zone Zone, existingNodes []roachpb.NodeDescriptor) []string {
+	localities := make([]roachpb.Locality, len(existingNodes))
+	for i := range existingNodes {
+		localities[i] = existingNodes[i].Locality
+	}
+	// Each replica must be on a different node, so a node that is counted
+	// toward one set of constraints is not counted toward another.
+	claimed := make([]bool, len(localities))
+
+	var suggestions []string
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		set := zone.ReplicaConstraints(i)
+		var suggested roachpb.Locality
+		for j, m := 0, set.ConstraintCount(); j < m; j++ {
+			constraint := set.Constraint(j)
+			if constraint.IsRequired() && constraint.GetKey() != "" {
+				suggested.Tiers = append(suggested.Tiers, roachpb.Tier{
+					Key: constraint.GetKey(), Value: constraint.GetValue(),
+				})
+			}
+		}
+		if len(suggested.Tiers) == 0 || !LocalitySatisfiesConstraints(suggested, set) {
+			// Either no locality can be derived from the set, or its constraints
+			// contradict each other and no locality can satisfy them.
+			continue
+		}
+
+		required := int(set.ReplicaCount())
+		if required == 0 {
+			required = int(EffectiveNumReplicas(zone, 1))
+		}
+		matching := 0
+		for j := range localities {
+			if matching < required && !claimed[j] && LocalitySatisfiesConstraints(localities[j], set) {
+				claimed[j] = true
+				matching++
+			}
+		}
+		for ; matching < required; matching++ {
+			suggestions = append(suggestions, suggested.String())
+		}
+	}
+	return suggestions
+}
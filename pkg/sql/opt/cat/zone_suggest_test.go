@@ -0,0 +1,95 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestSuggestNodesToSatisfyZone(t *testing.T) {
+	nodes := func(localities ...string) []roachpb.NodeDescriptor {
+		descs := make([]roachpb.NodeDescriptor, len(localities))
+		for i, s := range localities {
+			if err := descs[i].Locality.Set(s); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return descs
+	}
+	usEast := nodes("region=us-east,zone=us-east-1a", "region=us-east,zone=us-east-1b")
+
+	testCases := []struct {
+		constraints string
+		numReplicas int32
+		nodes       []roachpb.NodeDescriptor
+		expected    []string
+	}{
+		{constraints: "[]", nodes: usEast},
+		{constraints: "[+region=us-east]", numReplicas: 2, nodes: usEast},
+		{
+			constraints: "[+region=us-east]",
+			numReplicas: 3,
+			nodes:       usEast,
+			expected:    []string{"region=us-east"},
+		},
+		{
+			constraints: "[+region=eu-west,+zone=eu-west-1a]",
+			nodes:       usEast,
+			expected:    []string{"region=eu-west,zone=eu-west-1a"},
+		},
+		{
+			constraints: `{"+region=us-east": 2, "+region=eu-west": 1}`,
+			numReplicas: 3,
+			nodes:       usEast,
+			expected:    []string{"region=eu-west"},
+		},
+		{
+			// The node counted toward the first set is not counted toward the
+			// second, since the replicas must be on different nodes.
+			constraints: `{"+region=us-east": 1, "+region=us-east,-zone=us-east-1b": 1}`,
+			numReplicas: 2,
+			nodes:       nodes("region=us-east,zone=us-east-1a"),
+			expected:    []string{"region=us-east"},
+		},
+		{
+			// No locality can be derived from sets without required constraints,
+			// or from contradictory sets.
+			constraints: `{"-region=us-east": 1, "+ssd": 1, "+region=eu-west,-region=eu-west": 1}`,
+			numReplicas: 3,
+			nodes:       usEast,
+		},
+	}
+
+	for _, tc := range testCases {
+		zone := config.ZoneConfig{NumReplicas: &tc.numReplicas}
+		if tc.constraints != "[]" {
+			var constraints config.ConstraintsList
+			if err := yaml.UnmarshalStrict([]byte(tc.constraints), &constraints); err != nil {
+				t.Fatal(err)
+			}
+			zone.Constraints = constraints.Constraints
+		}
+		actual := cat.SuggestNodesToSatisfyZone(&zone, tc.nodes)
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.constraints, tc.expected, actual)
+		}
+	}
+}
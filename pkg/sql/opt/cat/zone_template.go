@@ -0,0 +1,197 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ZoneTemplate is a predefined zone for a common multi-region replica
+// placement pattern. The constraint values of the template refer to the
+// regions it is given by position, using fmt verbs like %[1]s, so the same
+// template can be used for any set of region names.
+type ZoneTemplate struct {
+	// Name is the name of the template, as used in
+	// CONFIGURE ZONE USING TEMPLATE.
+	Name string
+
+	// Regions describes the regions that the template takes, in order.
+	Regions []string
+
+	zone Zone
+}
+
+// ZoneConfigTemplateLibrary lists the predefined zone templates, ordered by
+// name.
+var ZoneConfigTemplateLibrary = []ZoneTemplate{
+	{
+		// Each range has five replicas, one in each region, so the data
+		// survives the loss of any two regions. Leases are kept in the primary
+		// region, and fail over to the secondary region.
+		Name:    "five_region_dr",
+		Regions: []string{"primary", "secondary", "region3", "region4", "region5"},
+		zone: WithLeasePreferenceFor(WithLeasePreferenceFor(
+			oneReplicaPerRegion(WithNumReplicas(&emptyZone{}, 5), 5),
+			"region", "%[1]s"),
+			"region", "%[2]s"),
+	},
+	{
+		// Each range has three replicas, all in one region, so the data
+		// survives the loss of a node but not of the region. Leases are kept
+		// in the region.
+		Name:    "single_region_ha",
+		Regions: []string{"region"},
+		zone: WithLeasePreferenceFor(
+			WithConstraintFor(WithNumReplicas(&emptyZone{}, 3), "region", "%[1]s", true),
+			"region", "%[1]s"),
+	},
+	{
+		// Each range has a replica in each of the three regions, and leases
+		// are spread across them.
+		Name:    "three_region_active_active",
+		Regions: []string{"region1", "region2", "region3"},
+		zone:    oneReplicaPerRegion(WithNumReplicas(&emptyZone{}, 3), 3),
+	},
+}
+
+// oneReplicaPerRegion adds one replica constraint set to the zone for each of
+// the first n regions of a template, which places one replica in each.
+func oneReplicaPerRegion(zone Zone, n int) Zone {
+	for i := 1; i <= n; i++ {
+		zone = WithReplicaConstraintFor(zone, 1, "region", fmt.Sprintf("%%[%d]s", i), true)
+	}
+	return zone
+}
+
+// LookupZoneTemplate returns the template in ZoneConfigTemplateLibrary with the
+// given name, or false if there is no such template.
+func LookupZoneTemplate(name string) (*ZoneTemplate, bool) {
+	for i := range ZoneConfigTemplateLibrary {
+		if ZoneConfigTemplateLibrary[i].Name == name {
+			return &ZoneConfigTemplateLibrary[i], true
+		}
+	}
+	return nil, false
+}
+
+// Zone returns the zone of the template for the given regions, which must be
+// as many as the template takes. The returned zone is a view over the
+// template.
+func (t *ZoneTemplate) Zone(regions ...string) (Zone, error) {
+	if len(regions) != len(t.Regions) {
+		return nil, fmt.Errorf("zone template %s takes %d regions (%s), got %d",
+			t.Name, len(t.Regions), strings.Join(t.Regions, ", "), len(regions))
+	}
+	args := make([]interface{}, len(regions))
+	for i := range regions {
+		args[i] = regions[i]
+	}
+	return &substitutedZone{Zone: t.zone, args: args}, nil
+}
+
+// emptyZone is a Zone that sets nothing. It is the base on which the templates
+// are built.
+type emptyZone struct{}
+
+var _ Zone = &emptyZone{}
+
+// ReplicaCount is part of the Zone interface.
+func (*emptyZone) ReplicaCount() int32 { return 0 }
+
+// ReplicaConstraintsCount is part of the Zone interface.
+func (*emptyZone) ReplicaConstraintsCount() int { return 0 }
+
+// ReplicaConstraints is part of the Zone interface.
+func (*emptyZone) ReplicaConstraints(i int) ReplicaConstraints {
+	panic("zone has no replica constraints")
+}
+
+// LeasePreferenceCount is part of the Zone interface.
+func (*emptyZone) LeasePreferenceCount() int { return 0 }
+
+// LeasePreference is part of the Zone interface.
+func (*emptyZone) LeasePreference(i int) ConstraintSet {
+	panic("zone has no lease preferences")
+}
+
+// ConstraintString is part of the Zone interface.
+func (*emptyZone) ConstraintString() string { return "" }
+
+// substitutedZone is a Zone whose constraint values are the values of the
+// zone it wraps, formatted with args. See ZoneTemplate.Zone.
+type substitutedZone struct {
+	Zone
+	args []interface{}
+}
+
+var _ Zone = &substitutedZone{}
+
+// ReplicaConstraints is part of the Zone interface.
+func (z *substitutedZone) ReplicaConstraints(i int) ReplicaConstraints {
+	replConstraint := z.Zone.ReplicaConstraints(i)
+	return &substitutedReplicaConstraints{
+		substitutedConstraintSet: substitutedConstraintSet{base: replConstraint, args: z.args},
+		replicaCount:             replConstraint.ReplicaCount(),
+	}
+}
+
+// LeasePreference is part of the Zone interface.
+func (z *substitutedZone) LeasePreference(i int) ConstraintSet {
+	return &substitutedConstraintSet{base: z.Zone.LeasePreference(i), args: z.args}
+}
+
+// ConstraintString is part of the Zone interface.
+func (z *substitutedZone) ConstraintString() string {
+	return FormatZoneConstraints(z)
+}
+
+// substitutedConstraintSet is a ConstraintSet whose constraint values are
+// formatted with args.
+type substitutedConstraintSet struct {
+	base ConstraintSet
+	args []interface{}
+}
+
+var _ ConstraintSet = &substitutedConstraintSet{}
+
+// ConstraintCount is part of the ConstraintSet interface.
+func (c *substitutedConstraintSet) ConstraintCount() int {
+	return c.base.ConstraintCount()
+}
+
+// Constraint is part of the ConstraintSet interface.
+func (c *substitutedConstraintSet) Constraint(i int) Constraint {
+	constraint := c.base.Constraint(i)
+	return &keyValueConstraint{
+		required: constraint.IsRequired(),
+		key:      constraint.GetKey(),
+		value:    fmt.Sprintf(constraint.GetValue(), c.args...),
+	}
+}
+
+// substitutedReplicaConstraints is a ReplicaConstraints whose constraint
+// values are formatted with args.
+type substitutedReplicaConstraints struct {
+	substitutedConstraintSet
+	replicaCount int32
+}
+
+var _ ReplicaConstraints = &substitutedReplicaConstraints{}
+
+// ReplicaCount is part of the ReplicaConstraints interface.
+func (c *substitutedReplicaConstraints) ReplicaCount() int32 {
+	return c.replicaCount
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+)
+
+func TestZoneConfigTemplateLibrary(t *testing.T) {
+	testCases := []struct {
+		name             string
+		regions          []string
+		numReplicas      int32
+		constraints      string
+		leasePreferences int
+		leasePreference  string
+	}{
+		{
+			name:             "five_region_dr",
+			regions:          []string{"a", "b", "c", "d", "e"},
+			numReplicas:      5,
+			constraints:      "{+region=a: 1, +region=b: 1, +region=c: 1, +region=d: 1, +region=e: 1}",
+			leasePreferences: 2,
+			leasePreference:  "[+region=a]",
+		},
+		{
+			name:             "single_region_ha",
+			regions:          []string{"east"},
+			numReplicas:      3,
+			constraints:      "[+region=east]",
+			leasePreferences: 1,
+			leasePreference:  "[+region=east]",
+		},
+		{
+			name:        "three_region_active_active",
+			regions:     []string{"east", "west", "central"},
+			numReplicas: 3,
+			constraints: "{+region=east: 1, +region=west: 1, +region=central: 1}",
+		},
+	}
+
+	if len(testCases) != len(cat.ZoneConfigTemplateLibrary) {
+		t.Fatalf("expected %d templates, got %d", len(testCases), len(cat.ZoneConfigTemplateLibrary))
+	}
+	if !sort.SliceIsSorted(cat.ZoneConfigTemplateLibrary, func(i, j int) bool {
+		return cat.ZoneConfigTemplateLibrary[i].Name < cat.ZoneConfigTemplateLibrary[j].Name
+	}) {
+		t.Error("expected templates to be ordered by name")
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			template, ok := cat.LookupZoneTemplate(tc.name)
+			if !ok {
+				t.Fatalf("template %s not found", tc.name)
+			}
+			if _, err := template.Zone(tc.regions[1:]...); err == nil {
+				t.Error("expected error for too few regions")
+			}
+
+			zone, err := template.Zone(tc.regions...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := cat.ValidateZone(zone); err != nil {
+				t.Error(err)
+			}
+			if zone.ReplicaCount() != tc.numReplicas {
+				t.Errorf("expected replica count %d, got %d", tc.numReplicas, zone.ReplicaCount())
+			}
+			if actual := cat.ZoneConstraintSummary(zone); actual != tc.constraints {
+				t.Errorf("expected constraints %s, got %s", tc.constraints, actual)
+			}
+			if zone.LeasePreferenceCount() != tc.leasePreferences {
+				t.Errorf("expected %d lease preferences, got %d", tc.leasePreferences, zone.LeasePreferenceCount())
+			}
+			if actual := cat.FormatZoneLeasePreference(zone); actual != tc.leasePreference {
+				t.Errorf("expected lease preference %s, got %s", tc.leasePreference, actual)
+			}
+		})
+	}
+
+	if _, ok := cat.LookupZoneTemplate("unknown"); ok {
+		t.Error("expected unknown template not to be found")
+	}
+}
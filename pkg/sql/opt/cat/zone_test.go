@@ -0,0 +1,685 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cat_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/treeprinter"
+	"github.com/gogo/protobuf/proto"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestFormatZone(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+
+	testCases := []struct {
+		zone     *config.ZoneConfig
+		expected string
+	}{
+		{
+			zone:     &config.ZoneConfig{},
+			expected: "ZONE\n",
+		},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "east")}},
+			}},
+			expected: "ZONE\n" +
+				" └── constraints: [+region=east]\n",
+		},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "east"), prohibited("dc", "west")}},
+			}},
+			expected: "ZONE\n" +
+				" └── constraints: [+region=east,-dc=west]\n",
+		},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("", "ssd"), prohibited("", "hdd")}},
+			}},
+			expected: "ZONE\n" +
+				" └── constraints: [+ssd,-hdd]\n",
+		},
+		{
+			zone: &config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{
+					{NumReplicas: 2, Constraints: []config.Constraint{required("region", "east")}},
+					{NumReplicas: 1, Constraints: []config.Constraint{
+						required("region", "west"), prohibited("", "hdd"),
+					}},
+				},
+			},
+			expected: "ZONE\n" +
+				" └── replica constraints\n" +
+				"      ├── 2 replicas: [+region=east]\n" +
+				"      └── 1 replicas: [+region=west,-hdd]\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tp := treeprinter.New()
+		cat.FormatZone(tc.zone, tp)
+		if actual := tp.String(); actual != tc.expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, actual)
+		}
+	}
+}
+
+func TestValidateZone(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+
+	testCases := []struct {
+		zone     *config.ZoneConfig
+		expected string
+	}{
+		{
+			zone:     &config.ZoneConfig{},
+			expected: "",
+		},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "east"), prohibited("region", "west")}},
+			}},
+			expected: "",
+		},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{NumReplicas: 1, Constraints: []config.Constraint{required("region", "east")}},
+				{NumReplicas: 1, Constraints: []config.Constraint{prohibited("region", "east")}},
+			}},
+			expected: "",
+		},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{
+					required("region", "east"),
+					{Type: config.Constraint_DEPRECATED_POSITIVE, Key: "region", Value: "east"},
+				}},
+			}},
+			expected: "",
+		},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{
+					required("region", "east"), required("dc", "1"), prohibited("region", "east"),
+				}},
+			}},
+			expected: "constraints [+region=east,+dc=1,-region=east] both require and prohibit region=east " +
+				"(replica constraints: {+region=east,+dc=1,-region=east: all replicas})",
+		},
+		{
+			zone: &config.ZoneConfig{LeasePreferences: []config.LeasePreference{
+				{Constraints: []config.Constraint{prohibited("", "ssd"), required("", "ssd")}},
+			}},
+			expected: "constraints [-ssd,+ssd] both require and prohibit ssd",
+		},
+	}
+
+	for i, tc := range testCases {
+		actual := ""
+		if err := cat.ValidateZone(tc.zone); err != nil {
+			actual = err.Error()
+		}
+		if actual != tc.expected {
+			t.Errorf("%d: expected %q, got %q", i, tc.expected, actual)
+		}
+	}
+}
+
+func TestEffectiveNumReplicas(t *testing.T) {
+	if actual := cat.EffectiveNumReplicas(&config.ZoneConfig{}, 3); actual != 3 {
+		t.Errorf("expected inherited zone to use default of 3 replicas, got %d", actual)
+	}
+	zone := &config.ZoneConfig{NumReplicas: proto.Int32(5)}
+	if actual := cat.EffectiveNumReplicas(zone, 3); actual != 5 {
+		t.Errorf("expected zone to use its own 5 replicas, got %d", actual)
+	}
+}
+
+func TestIsPinned(t *testing.T) {
+	required := config.Constraint{Type: config.Constraint_REQUIRED, Key: "region", Value: "east"}
+	prohibited := config.Constraint{Type: config.Constraint_PROHIBITED, Key: "region", Value: "west"}
+
+	testCases := []struct {
+		zone     config.ZoneConfig
+		expected bool
+	}{
+		{zone: config.ZoneConfig{}, expected: false},
+		{zone: config.ZoneConfig{
+			LeasePreferences: []config.LeasePreference{{Constraints: []config.Constraint{required}}},
+		}, expected: false},
+		{zone: config.ZoneConfig{
+			Constraints: []config.Constraints{{Constraints: []config.Constraint{prohibited}}},
+		}, expected: false},
+		{zone: config.ZoneConfig{
+			Constraints: []config.Constraints{{Constraints: []config.Constraint{prohibited, required}}},
+		}, expected: true},
+		{zone: config.ZoneConfig{
+			Constraints: []config.Constraints{
+				{NumReplicas: 1, Constraints: []config.Constraint{prohibited}},
+				{NumReplicas: 2, Constraints: []config.Constraint{required}},
+			},
+		}, expected: true},
+	}
+
+	for i, tc := range testCases {
+		if actual := cat.IsPinned(&tc.zone); actual != tc.expected {
+			t.Errorf("%d: expected %v, got %v", i, tc.expected, actual)
+		}
+	}
+}
+
+func TestConstraintString(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+	perReplica := &config.ZoneConfig{
+		NumReplicas: proto.Int32(3),
+		Constraints: []config.Constraints{
+			{NumReplicas: 2, Constraints: []config.Constraint{required("region", "east")}},
+			{NumReplicas: 1, Constraints: []config.Constraint{required("region", "west")}},
+		},
+	}
+	dc := prohibited("dc", "1")
+
+	testCases := []struct {
+		zone     cat.Zone
+		expected string
+	}{
+		{zone: &config.ZoneConfig{}, expected: ""},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "east"), prohibited("", "ssd")}},
+			}},
+			expected: "[+region=east,-ssd]",
+		},
+		{zone: perReplica, expected: "[+region=east]"},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{NumReplicas: 1},
+				{NumReplicas: 2, Constraints: []config.Constraint{required("region", "west")}},
+			}},
+			expected: "[+region=west]",
+		},
+		{
+			zone:     cat.AddConstraint(&config.ZoneConfig{}, &dc),
+			expected: "[-dc=1]",
+		},
+		{
+			zone:     cat.WithReplicaConstraintFor(&config.ZoneConfig{}, 1, "region", "east", true /* required */),
+			expected: "[+region=east]",
+		},
+		{zone: cat.WithoutLeasePreferences(perReplica), expected: "[+region=east]"},
+	}
+
+	for _, tc := range testCases {
+		if actual := tc.zone.ConstraintString(); actual != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, actual)
+		}
+	}
+}
+
+func TestZoneConstraintSummary(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+
+	testCases := []struct {
+		zone     *config.ZoneConfig
+		expected string
+	}{
+		{zone: &config.ZoneConfig{}, expected: "[]"},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "east"), prohibited("", "ssd")}},
+			}},
+			expected: "[+region=east,-ssd]",
+		},
+		{
+			zone: &config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{
+					{NumReplicas: 2, Constraints: []config.Constraint{required("region", "east")}},
+					{NumReplicas: 1, Constraints: []config.Constraint{
+						required("region", "west"), required("dc", "1"),
+					}},
+				},
+			},
+			expected: "{+region=east: 2, +region=west,+dc=1: 1}",
+		},
+	}
+
+	for _, tc := range testCases {
+		if actual := cat.ZoneConstraintSummary(tc.zone); actual != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, actual)
+		}
+	}
+}
+
+func TestZoneReplicaSummary(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+
+	testCases := []struct {
+		zone     *config.ZoneConfig
+		expected string
+	}{
+		{zone: &config.ZoneConfig{}, expected: "{}"},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "us-east")}},
+			}},
+			expected: "{+region=us-east: all replicas}",
+		},
+		{
+			zone: &config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{
+					{NumReplicas: 2, Constraints: []config.Constraint{required("region", "us-east")}},
+					{NumReplicas: 1, Constraints: []config.Constraint{required("region", "us-west")}},
+				},
+			},
+			expected: "{+region=us-east: 2 replicas, +region=us-west: 1 replica}",
+		},
+	}
+
+	for _, tc := range testCases {
+		if actual := cat.ZoneReplicaSummary(tc.zone); actual != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, actual)
+		}
+	}
+}
+
+func TestFormatZoneAsComment(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+
+	testCases := []struct {
+		zone     *config.ZoneConfig
+		expected string
+	}{
+		{zone: &config.ZoneConfig{}, expected: "/* zone: [] */"},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "us-east"), prohibited("", "ssd")}},
+			}},
+			expected: "/* zone: [+region=us-east,-ssd] */",
+		},
+		{
+			zone: &config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{
+					{NumReplicas: 2, Constraints: []config.Constraint{required("region", "us-east")}},
+					{NumReplicas: 1, Constraints: []config.Constraint{required("region", "us-west")}},
+				},
+			},
+			expected: "/* zone: [+region=us-east, 2 replicas], [+region=us-west, 1 replica] */",
+		},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "*/ DROP TABLE t; /*")}},
+			}},
+			expected: "/* zone: [+region=* / DROP TABLE t; / *] */",
+		},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "/*/ DROP TABLE t; */")}},
+			}},
+			expected: "/* zone: [+region=/ * / DROP TABLE t; * /] */",
+		},
+	}
+
+	for _, tc := range testCases {
+		actual := cat.FormatZoneAsComment(tc.zone)
+		if actual != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, actual)
+			continue
+		}
+
+		// Verify that the comment ends where it is expected to, so that none of
+		// the constraints end up outside of it.
+		stmts, err := parser.Parse("SELECT 1 " + actual)
+		if err != nil {
+			t.Fatalf("%s: %v", actual, err)
+		}
+		if len(stmts) != 1 || stmts[0].AST.String() != "SELECT 1" {
+			t.Errorf("%s: expected SELECT 1, got %v", actual, stmts)
+		}
+	}
+}
+
+func TestZoneToJSON(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+
+	testCases := []struct {
+		zone     *config.ZoneConfig
+		expected string
+	}{
+		{
+			zone:     &config.ZoneConfig{},
+			expected: `{"constraints": [], "lease_preferences": [], "num_replicas": 0}`,
+		},
+		{
+			zone: &config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{
+					{NumReplicas: 2, Constraints: []config.Constraint{required("region", "us-east")}},
+					{NumReplicas: 1, Constraints: []config.Constraint{required("region", "us-west"), prohibited("", "ssd")}},
+				},
+				LeasePreferences: []config.LeasePreference{
+					{Constraints: []config.Constraint{required("region", "us-east")}},
+				},
+			},
+			expected: `{"constraints": [` +
+				`{"constraints": ["+region=us-east"], "num_replicas": 2}, ` +
+				`{"constraints": ["+region=us-west", "-ssd"], "num_replicas": 1}], ` +
+				`"lease_preferences": [["+region=us-east"]], "num_replicas": 3}`,
+		},
+		{
+			zone: &config.ZoneConfig{
+				Constraints: []config.Constraints{
+					{Constraints: []config.Constraint{
+						{Type: config.Constraint_DEPRECATED_POSITIVE, Key: "region", Value: "us-east"},
+						prohibited("dc", "1"),
+					}},
+				},
+			},
+			expected: `{"constraints": [` +
+				`{"constraints": ["region=us-east", "-dc=1"], "num_replicas": 0}], ` +
+				`"lease_preferences": [], "num_replicas": 0}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		if actual := cat.ZoneToJSON(tc.zone).String(); actual != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, actual)
+		}
+	}
+}
+
+func TestAddConstraint(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	dc := required("dc", "1")
+
+	testCases := []struct {
+		zone     *config.ZoneConfig
+		expected string
+	}{
+		{zone: &config.ZoneConfig{}, expected: "[+dc=1]"},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{required("region", "east")}},
+			}},
+			expected: "[+region=east,+dc=1]",
+		},
+		{
+			zone: &config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{
+					{NumReplicas: 2, Constraints: []config.Constraint{required("region", "east")}},
+					{NumReplicas: 1, Constraints: []config.Constraint{required("region", "west")}},
+				},
+			},
+			expected: "{+region=east,+dc=1: 2, +region=west: 1}",
+		},
+	}
+
+	for i, tc := range testCases {
+		before := cat.ZoneConstraintSummary(tc.zone)
+		zone := cat.AddConstraint(tc.zone, &dc)
+		if actual := cat.ZoneConstraintSummary(zone); actual != tc.expected {
+			t.Errorf("%d: expected %s, got %s", i, tc.expected, actual)
+		}
+		if zone.ReplicaCount() != tc.zone.ReplicaCount() {
+			t.Errorf("%d: expected replica count %d, got %d", i, tc.zone.ReplicaCount(), zone.ReplicaCount())
+		}
+		if after := cat.ZoneConstraintSummary(tc.zone); after != before {
+			t.Errorf("%d: original zone was modified from %s to %s", i, before, after)
+		}
+	}
+}
+
+func TestWithConstraintFor(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+
+	base := &config.ZoneConfig{Constraints: []config.Constraints{
+		{Constraints: []config.Constraint{required("region", "east")}},
+	}}
+	before := cat.ZoneConstraintSummary(base)
+
+	zone := cat.WithNumReplicas(
+		cat.WithConstraintFor(cat.WithConstraintFor(base, "dc", "1", true), "", "ssd", false), 5,
+	)
+	const expected = "[+region=east,+dc=1,-ssd]"
+	if actual := cat.ZoneConstraintSummary(zone); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+	if zone.ReplicaCount() != 5 {
+		t.Errorf("expected replica count 5, got %d", zone.ReplicaCount())
+	}
+	if after := cat.ZoneConstraintSummary(base); after != before {
+		t.Errorf("original zone was modified from %s to %s", before, after)
+	}
+	if base.ReplicaCount() != 0 {
+		t.Errorf("original replica count was modified to %d", base.ReplicaCount())
+	}
+}
+
+func TestWithoutLeasePreferences(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	format := func(zone cat.Zone) string {
+		return cat.ZoneConfigExplain(zone, roachpb.Locality{})
+	}
+
+	zone := &config.ZoneConfig{
+		NumReplicas: proto.Int32(3),
+		Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{required("region", "east")}},
+		},
+		LeasePreferences: []config.LeasePreference{
+			{Constraints: []config.Constraint{required("region", "east"), required("dc", "1")}},
+		},
+	}
+	other := &config.ZoneConfig{
+		NumReplicas: proto.Int32(3),
+		Constraints: []config.Constraints{
+			{Constraints: []config.Constraint{required("region", "east")}},
+		},
+	}
+
+	before := format(zone)
+	stripped := cat.WithoutLeasePreferences(zone)
+	const expected = "all replicas required in region=east."
+	if actual := format(stripped); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+	if stripped.ReplicaCount() != zone.ReplicaCount() {
+		t.Errorf("expected replica count %d, got %d", zone.ReplicaCount(), stripped.ReplicaCount())
+	}
+	if after := format(zone); after != before {
+		t.Errorf("original zone was modified from %s to %s", before, after)
+	}
+
+	// The zones only differ in their lease preferences.
+	if cat.ZoneCompare(zone, other) == 0 {
+		t.Error("expected zones to differ")
+	}
+	if cat.ZoneCompare(cat.WithoutLeasePreferences(zone), cat.WithoutLeasePreferences(other)) != 0 {
+		t.Error("expected zones without lease preferences to be equal")
+	}
+}
+
+func TestNormalizeZone(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+	format := func(zone cat.Zone) string {
+		return cat.ZoneConfigExplain(zone, roachpb.Locality{})
+	}
+
+	testCases := []struct {
+		zone     *config.ZoneConfig
+		expected string
+	}{
+		{zone: &config.ZoneConfig{}, expected: "replicas may be placed on any node."},
+		{
+			zone: &config.ZoneConfig{Constraints: []config.Constraints{
+				{Constraints: []config.Constraint{
+					required("region", "east"), prohibited("dc", "1"), required("az", "1"), required("dc", "1"),
+				}},
+			}},
+			expected: "all replicas required in az=1 and dc=1 and region=east and prohibited in dc=1.",
+		},
+		{
+			// Lease preferences keep their order, but their constraints are
+			// sorted.
+			zone: &config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{
+					{NumReplicas: 2, Constraints: []config.Constraint{required("region", "west")}},
+					{NumReplicas: 1, Constraints: []config.Constraint{required("region", "east"), required("az", "1")}},
+				},
+				LeasePreferences: []config.LeasePreference{
+					{Constraints: []config.Constraint{required("region", "west")}},
+					{Constraints: []config.Constraint{required("region", "east"), required("az", "1")}},
+				},
+			},
+			expected: "1 replica required in az=1 and region=east; 2 replicas required in region=west; " +
+				"leases preferred in region=west; otherwise leases preferred in az=1 and region=east.",
+		},
+	}
+
+	for i, tc := range testCases {
+		before := format(tc.zone)
+		zone := cat.NormalizeZone(tc.zone)
+		if actual := format(zone); actual != tc.expected {
+			t.Errorf("%d: expected\n%s\ngot\n%s", i, tc.expected, actual)
+		}
+		if zone.ReplicaCount() != tc.zone.ReplicaCount() {
+			t.Errorf("%d: expected replica count %d, got %d", i, tc.zone.ReplicaCount(), zone.ReplicaCount())
+		}
+		if cat.ZoneCompare(cat.NormalizeZone(zone), zone) != 0 {
+			t.Errorf("%d: expected normalizing twice to have no effect", i)
+		}
+		if after := format(tc.zone); after != before {
+			t.Errorf("%d: original zone was modified from\n%s\nto\n%s", i, before, after)
+		}
+	}
+}
+
+func TestFormatReplicaConstraintsAsSQL(t *testing.T) {
+	required := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: key, Value: value}
+	}
+	prohibited := func(key, value string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_PROHIBITED, Key: key, Value: value}
+	}
+
+	testCases := []struct {
+		constraints config.Constraints
+		expected    string
+	}{
+		{
+			constraints: config.Constraints{Constraints: []config.Constraint{
+				required("region", "east"), prohibited("", "ssd"),
+			}},
+			expected: `'[+region=east,-ssd]'`,
+		},
+		{
+			constraints: config.Constraints{NumReplicas: 2, Constraints: []config.Constraint{
+				required("region", "east"), prohibited("dc", "1"),
+			}},
+			expected: `'{"+region=east,-dc=1": 2}'`,
+		},
+		{
+			constraints: config.Constraints{NumReplicas: 1, Constraints: []config.Constraint{
+				required("rack", `it's "1"`),
+			}},
+			expected: `e'{"+rack=it\'s \\"1\\"": 1}'`,
+		},
+		{
+			constraints: config.Constraints{NumReplicas: 1, Constraints: []config.Constraint{
+				{Type: config.Constraint_DEPRECATED_POSITIVE, Key: "region", Value: "east"}, prohibited("dc", "1"),
+			}},
+			expected: `'{"region=east,-dc=1": 1}'`,
+		},
+	}
+
+	for _, tc := range testCases {
+		actual := cat.FormatReplicaConstraintsAsSQL(&tc.constraints)
+		if actual != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, actual)
+			continue
+		}
+
+		// Verify that the SQL parser accepts the literal, and that it decodes to
+		// the original constraints.
+		stmt, err := parser.ParseOne("ALTER TABLE t CONFIGURE ZONE USING constraints = " + actual)
+		if err != nil {
+			t.Fatalf("%s: %v", actual, err)
+		}
+		value := stmt.AST.(*tree.SetZoneConfig).Options[0].Value.(*tree.StrVal).RawString()
+		var list config.ConstraintsList
+		if err := yaml.UnmarshalStrict([]byte(value), &list); err != nil {
+			t.Fatalf("%s: %v", actual, err)
+		}
+		if len(list.Constraints) != 1 || !list.Constraints[0].Equal(&tc.constraints) {
+			t.Errorf("%s: expected %v, got %v", actual, tc.constraints, list.Constraints)
+		}
+	}
+}
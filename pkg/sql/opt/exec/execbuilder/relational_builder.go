@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
@@ -375,9 +376,12 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 	needed, output := b.getColumns(scan.Cols, scan.Table)
 	res := execPlan{outputCols: output}
 
+	idx := tab.Index(scan.Index)
+	b.traceZoneLocalityScore(tab, idx)
+
 	root, err := b.factory.ConstructScan(
 		tab,
-		tab.Index(scan.Index),
+		idx,
 		needed,
 		scan.Constraint,
 		scan.HardLimit.RowCount(),
@@ -393,6 +397,19 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 	return res, nil
 }
 
+// traceZoneLocalityScore records in the session trace how well the gateway's
+// locality matches the zone constraints of the scanned index, as computed by
+// cat.LocalityScore. This is the score the coster used to prefer one index over
+// another, so it helps explain index choices in multi-region clusters.
+func (b *Builder) traceZoneLocalityScore(tab cat.Table, idx cat.Index) {
+	ctx := b.evalCtx.Context
+	if ctx == nil || len(b.evalCtx.Locality.Tiers) == 0 || !log.HasSpanOrEvent(ctx) {
+		return
+	}
+	log.VEventf(ctx, 2, "scan %s@%s: zone_locality_score=%.2f",
+		tab.Name(), idx.Name(), cat.LocalityScore(idx.Zone(), b.evalCtx.Locality))
+}
+
 func (b *Builder) buildVirtualScan(scan *memo.VirtualScanExpr) (execPlan, error) {
 	md := b.mem.Metadata()
 	tab := md.Table(scan.Table)
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -406,6 +407,12 @@ func (h *hasher) HashTypedExpr(val tree.TypedExpr) {
 	h.HashUint64(uint64(reflect.ValueOf(val).Pointer()))
 }
 
+func (h *hasher) HashZone(val cat.Zone) {
+	if val != nil {
+		h.HashUint64(uint64(reflect.ValueOf(val).Pointer()))
+	}
+}
+
 func (h *hasher) HashColumnID(val opt.ColumnID) {
 	h.HashUint64(uint64(val))
 }
@@ -696,6 +703,10 @@ func (h *hasher) IsTypedExprEqual(l, r tree.TypedExpr) bool {
 	return l == r
 }
 
+func (h *hasher) IsZoneEqual(l, r cat.Zone) bool {
+	return l == r
+}
+
 func (h *hasher) IsColumnIDEqual(l, r opt.ColumnID) bool {
 	return l == r
 }
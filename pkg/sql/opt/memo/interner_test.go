@@ -21,6 +21,7 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
@@ -109,6 +110,9 @@ func TestInterner(t *testing.T) {
 	}
 	aggs5 := AggregationsExpr{{Agg: &CountRowsExpr{}, ColPrivate: ColPrivate{Col: 1}}}
 
+	zone1 := &config.ZoneConfig{}
+	zone2 := &config.ZoneConfig{}
+
 	type testVariation struct {
 		val1  interface{}
 		val2  interface{}
@@ -252,6 +256,11 @@ func TestInterner(t *testing.T) {
 			{val1: tup2, val2: tup3, equal: false},
 		}},
 
+		{hashFn: in.hasher.HashZone, eqFn: in.hasher.IsZoneEqual, variations: []testVariation{
+			{val1: zone1, val2: zone1, equal: true},
+			{val1: zone1, val2: zone2, equal: false},
+		}},
+
 		{hashFn: in.hasher.HashColumnID, eqFn: in.hasher.IsColumnIDEqual, variations: []testVariation{
 			{val1: opt.ColumnID(0), val2: opt.ColumnID(0), equal: true},
 			{val1: opt.ColumnID(0), val2: opt.ColumnID(1), equal: false},
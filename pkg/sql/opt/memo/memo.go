@@ -17,6 +17,7 @@ package memo
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
@@ -140,6 +141,11 @@ type Memo struct {
 	zigzagJoinEnabled bool
 	safeUpdates       bool
 
+	// locality is the locality given by the client with the crdb_locality
+	// connection parameter, which queries are planned for instead of the
+	// gateway node's locality.
+	locality roachpb.Locality
+
 	// curID is the highest currently in-use scalar expression ID.
 	curID opt.ScalarID
 }
@@ -161,6 +167,7 @@ func (m *Memo) Init(evalCtx *tree.EvalContext) {
 	m.dataConversion = evalCtx.SessionData.DataConversion
 	m.reorderJoinsLimit = evalCtx.SessionData.ReorderJoinsLimit
 	m.zigzagJoinEnabled = evalCtx.SessionData.ZigzagJoinEnabled
+	m.locality = evalCtx.SessionData.Locality
 }
 
 // IsEmpty returns true if there are no expressions in the memo.
@@ -245,6 +252,10 @@ func (m *Memo) HasPlaceholders() bool {
 //      compiled.
 //   5. Data source privileges: current user may no longer have access to one or
 //      more data sources.
+//   6. Zone configs: a change to any zone config can change where data is
+//      placed, and therefore which plan is best.
+//   7. Session locality: the crdb_locality connection parameter changes which
+//      replicas are local to the gateway, and therefore which plan is best.
 //
 // This function cannot swallow errors and return only a boolean, as it may
 // perform KV operations on behalf of the transaction associated with the
@@ -257,7 +268,8 @@ func (m *Memo) IsStale(
 	if !m.dataConversion.Equals(&evalCtx.SessionData.DataConversion) ||
 		m.reorderJoinsLimit != evalCtx.SessionData.ReorderJoinsLimit ||
 		m.zigzagJoinEnabled != evalCtx.SessionData.ZigzagJoinEnabled ||
-		m.safeUpdates != evalCtx.SessionData.SafeUpdates {
+		m.safeUpdates != evalCtx.SessionData.SafeUpdates ||
+		!m.locality.Equals(evalCtx.SessionData.Locality) {
 		return true, nil
 	}
 
@@ -19,6 +19,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	opttestutils "github.com/cockroachdb/cockroach/pkg/sql/opt/testutils"
@@ -161,6 +162,12 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData.SafeUpdates = false
 	notStale()
 
+	// Stale session locality.
+	evalCtx.SessionData.Locality = roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: "east"}}}
+	stale()
+	evalCtx.SessionData.Locality = roachpb.Locality{}
+	notStale()
+
 	// Stale data sources and schema. Create new catalog so that data sources are
 	// recreated and can be modified independently.
 	catalog = testcat.New()
@@ -207,6 +214,11 @@ func TestMemoIsStale(t *testing.T) {
 	}
 	catalog.Schema().Revoked = false
 	notStale()
+
+	// Zone config changes. There is no way to undo the change, so this must be
+	// the last check.
+	catalog.IncrementZoneConfigVersion()
+	stale()
 }
 
 // runDataDrivenTest runs data-driven testcases of the form
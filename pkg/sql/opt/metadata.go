@@ -97,6 +97,10 @@ type Metadata struct {
 	// needed for EXPLAIN (opt, env).
 	views []cat.View
 
+	// zoneConfigVersion is the version of the catalog's zone configs at the
+	// time that the query was built. See cat.Catalog.ZoneConfigVersion.
+	zoneConfigVersion uint64
+
 	// NOTE! When adding fields here, update CopyFrom.
 }
 
@@ -138,6 +142,7 @@ func (md *Metadata) Init() {
 	md.tables = md.tables[:0]
 	md.views = md.views[:0]
 	md.deps = md.deps[:0]
+	md.zoneConfigVersion = 0
 }
 
 // CopyFrom initializes the metadata with a copy of the provided metadata.
@@ -163,6 +168,14 @@ func (md *Metadata) CopyFrom(from *Metadata) {
 
 	md.sequences = append(md.sequences, from.sequences...)
 	md.deps = append(md.deps, from.deps...)
+	md.zoneConfigVersion = from.zoneConfigVersion
+}
+
+// SetZoneConfigVersion records the version of the catalog's zone configs that
+// the query is built against. If the Memo using this metadata is cached, then a
+// call to CheckDependencies can detect that a zone config has changed since.
+func (md *Metadata) SetZoneConfigVersion(version uint64) {
+	md.zoneConfigVersion = version
 }
 
 // AddDataSourceDependency tracks one of the catalog data sources on which the
@@ -209,7 +222,8 @@ func (md *Metadata) AddSchemaDependency(name *cat.SchemaName, s cat.Schema, priv
 // depends, in order to check that the fully qualified object names still
 // resolve to the same version of the same objects, and that the user still has
 // sufficient privileges to access the objects. If the dependencies are no
-// longer up-to-date, then CheckDependencies returns false.
+// longer up-to-date, then CheckDependencies returns false. The dependencies are
+// also out of date if any zone config has changed since the query was built.
 //
 // This function cannot swallow errors and return only a boolean, as it may
 // perform KV operations on behalf of the transaction associated with the
@@ -217,6 +231,10 @@ func (md *Metadata) AddSchemaDependency(name *cat.SchemaName, s cat.Schema, priv
 func (md *Metadata) CheckDependencies(
 	ctx context.Context, catalog cat.Catalog,
 ) (upToDate bool, err error) {
+	if catalog.ZoneConfigVersion() != md.zoneConfigVersion {
+		return false, nil
+	}
+
 	for i := range md.deps {
 		obj := md.deps[i].object
 		var toCheck cat.Object
@@ -147,6 +147,8 @@ func (b *Builder) Build() (err error) {
 		}
 	}()
 
+	b.factory.Metadata().SetZoneConfigVersion(b.catalog.ZoneConfigVersion())
+
 	// Special case for CannedOptPlan.
 	if canned, ok := b.stmt.(*tree.CannedOptPlan); ok {
 		b.factory.DisableOptimizations()
@@ -361,7 +361,11 @@ func (b *Builder) buildScan(
 		private := memo.VirtualScanPrivate{Table: tabID, Cols: tabColIDs}
 		outScope.expr = b.factory.ConstructVirtualScan(&private)
 	} else {
-		private := memo.ScanPrivate{Table: tabID, Cols: tabColIDs}
+		private := memo.ScanPrivate{
+			Table: tabID,
+			Zone:  tab.Index(cat.PrimaryIndex).Zone(),
+			Cols:  tabColIDs,
+		}
 
 		if indexFlags != nil {
 			private.Flags.NoIndexJoin = indexFlags.NoIndexJoin
@@ -170,6 +170,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"ColType":        {fullName: "coltypes.T", isPointer: true},
 		"Datum":          {fullName: "tree.Datum", isPointer: true},
 		"TypedExpr":      {fullName: "tree.TypedExpr", isPointer: true},
+		"Zone":           {fullName: "cat.Zone", isPointer: true},
 		"Subquery":       {fullName: "*tree.Subquery", isPointer: true, usePointerIntern: true},
 		"CreateTable":    {fullName: "*tree.CreateTable", isPointer: true, usePointerIntern: true},
 		"Constraint":     {fullName: "*constraint.Constraint", isPointer: true, usePointerIntern: true},
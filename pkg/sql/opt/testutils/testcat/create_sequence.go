@@ -14,7 +14,10 @@
 
 package testcat
 
-import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+import (
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
 
 // CreateSequence creates a test sequence from a parsed DDL statement and adds it
 // the catalog. This is intended for testing, and is not a complete (and
@@ -26,6 +29,7 @@ func (tc *Catalog) CreateSequence(stmt *tree.CreateSequence) *Sequence {
 		SeqID:   tc.nextStableID(),
 		SeqName: stmt.Name,
 		Catalog: tc,
+		SeqZone: &config.ZoneConfig{},
 	}
 
 	tc.AddSequence(seq)
@@ -17,6 +17,7 @@ package testcat
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/config"
@@ -41,6 +42,8 @@ type Catalog struct {
 	testSchema  Schema
 	dataSources map[string]cat.DataSource
 	counter     int
+
+	zoneConfigVersion uint64
 }
 
 var _ cat.Catalog = &Catalog{}
@@ -163,6 +166,35 @@ func (tc *Catalog) ResolveDataSourceByID(
 		"relation [%d] does not exist", id)
 }
 
+// ZoneConfigVersion is part of the cat.Catalog interface.
+func (tc *Catalog) ZoneConfigVersion() uint64 {
+	return tc.zoneConfigVersion
+}
+
+// IncrementZoneConfigVersion simulates a change to a zone config, so that
+// queries built before the call are considered stale.
+func (tc *Catalog) IncrementZoneConfigVersion() {
+	tc.zoneConfigVersion++
+}
+
+// TablesWithZoneConfig is part of the cat.Catalog interface.
+func (tc *Catalog) TablesWithZoneConfig(ctx context.Context, dbName string) ([]cat.Table, error) {
+	if dbName != testDB {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidCatalogNameError,
+			"database %q does not exist", dbName)
+	}
+	var tables []cat.Table
+	for _, ds := range tc.dataSources {
+		if tab, ok := ds.(*Table); ok && tab.zoneConfigured {
+			tables = append(tables, tab)
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].ID() < tables[j].ID()
+	})
+	return tables, nil
+}
+
 // CheckPrivilege is part of the cat.Catalog interface.
 func (tc *Catalog) CheckPrivilege(ctx context.Context, o cat.Object, priv privilege.Kind) error {
 	switch t := o.(type) {
@@ -484,6 +516,10 @@ type Table struct {
 	// referenced is set to true when another table has referenced this table
 	// via a foreign key.
 	referenced bool
+
+	// zoneConfigured is set to true when a zone config has been set on one of
+	// the table's indexes.
+	zoneConfigured bool
 }
 
 var _ cat.Table = &Table{}
@@ -838,6 +874,10 @@ type Sequence struct {
 	SeqName    tree.TableName
 	Catalog    cat.Catalog
 
+	// SeqZone is the zone associated with the sequence. This may be inherited
+	// from the parent database, or even the default zone.
+	SeqZone *config.ZoneConfig
+
 	// If Revoked is true, then the user has had privileges on the sequence revoked.
 	Revoked bool
 }
@@ -868,6 +908,11 @@ func (ts *Sequence) SequenceName() *tree.TableName {
 	return ts.Name()
 }
 
+// Zone is part of the cat.Sequence interface.
+func (ts *Sequence) Zone() cat.Zone {
+	return ts.SeqZone
+}
+
 func (ts *Sequence) String() string {
 	tp := treeprinter.New()
 	cat.FormatSequence(ts.Catalog, ts, tp)
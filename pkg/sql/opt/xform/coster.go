@@ -25,7 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/ordering"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
-	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
@@ -70,19 +70,26 @@ type Coster interface {
 type coster struct {
 	mem *memo.Memo
 
-	// locality gives the location of the current node as a set of user-defined
-	// key/value pairs, ordered from most inclusive to least inclusive. If there
-	// are no tiers, then the node's location is not known. Example:
+	// zoneCtx gives the location of the current node in its GatewayLocality,
+	// as a set of user-defined key/value pairs, ordered from most inclusive to
+	// least inclusive. If there are no tiers, then the node's location is not
+	// known. Example:
 	//
 	//   [region=us,dc=east]
 	//
-	locality roachpb.Locality
+	zoneCtx cat.ZoneConstraintEvalContext
 
 	// perturbation indicates how much to randomly perturb the cost. It is used
 	// to generate alternative plans for testing. For example, if perturbation is
 	// 0.5, and the estimated cost of an expression is c, the cost returned by
 	// ComputeCost will be in the range [c - 0.5 * c, c + 0.5 * c).
 	perturbation float64
+
+	// zoneGraph groups the tables in the memo's metadata by the placement of
+	// their data. It is built lazily by colocated; zoneGraphTables is the number
+	// of tables that have been added to it so far.
+	zoneGraph       cat.ZoneConstraintGraph
+	zoneGraphTables int
 }
 
 var _ Coster = &coster{}
@@ -122,6 +129,23 @@ const (
 	// up with better way to incorporate latency into the coster.
 	latencyCostFactor = cpuCostFactor
 
+	// pinnedLatencyCostFactor is used in place of latencyCostFactor for indexes
+	// whose zones pin replicas to particular localities (see cat.IsPinned). If
+	// such an index is pinned away from the current locality, then none of its
+	// replicas can be local. An index that is not pinned might still have a
+	// nearby replica, even if its lease preferences point elsewhere.
+	pinnedLatencyCostFactor = 2 * latencyCostFactor
+
+	// voterReadAmplification and nonVoterReadAmplification scale the latency
+	// cost of reading from an index that does not fully match the current
+	// locality (see remoteReadAmplification). A remote read may be served by a
+	// follower, which has to read from its Raft log or wait for the closed
+	// timestamp to catch up. If the gateway's locality cannot hold one of the
+	// index's replicas at all, then the read always needs an extra network
+	// round trip, so it is amplified further.
+	voterReadAmplification    = 1.25
+	nonVoterReadAmplification = 2
+
 	// hugeCost is used with expressions we want to avoid; these are expressions
 	// that "violate" a hint like forcing a specific index or join algorithm.
 	// If the final expression has this cost or larger, it means that there was no
@@ -130,10 +154,14 @@ const (
 )
 
 // Init initializes a new coster structure with the given memo.
-func (c *coster) Init(evalCtx *tree.EvalContext, mem *memo.Memo, perturbation float64) {
+func (c *coster) Init(
+	zoneCtx cat.ZoneConstraintEvalContext, mem *memo.Memo, perturbation float64,
+) {
 	c.mem = mem
-	c.locality = evalCtx.Locality
+	c.zoneCtx = zoneCtx
 	c.perturbation = perturbation
+	c.zoneGraph = cat.ZoneConstraintGraph{}
+	c.zoneGraphTables = 0
 }
 
 // ComputeCost calculates the estimated cost of the top-level operator in a
@@ -267,7 +295,7 @@ func (c *coster) computeScanCost(scan *memo.ScanExpr, required *physical.Require
 		return hugeCost
 	}
 	rowCount := scan.Relational().Stats.RowCount
-	perRowCost := c.rowScanCost(scan.Table, scan.Index, scan.Cols.Len())
+	perRowCost := c.rowScanCost(scan.Table, scan.Index, scan.Zone, scan.Cols.Len())
 
 	if ordering.ScanIsReverse(scan, &required.Ordering) {
 		if rowCount > 1 {
@@ -330,6 +358,12 @@ func (c *coster) computeHashJoinCost(join memo.RelExpr) memo.Cost {
 	// TODO(radu): ideally we would have an estimate of how many rows we actually
 	// have to run the ON condition on.
 	cost += memo.Cost(join.Relational().Stats.RowCount) * cpuCostFactor
+
+	cost += c.colocationCost(
+		join.Child(0).(memo.RelExpr).Relational().OutputCols,
+		join.Child(1).(memo.RelExpr).Relational().OutputCols,
+		math.Min(leftRowCount, rightRowCount),
+	)
 	return cost
 }
 
@@ -343,6 +377,13 @@ func (c *coster) computeMergeJoinCost(join *memo.MergeJoinExpr) memo.Cost {
 	// TODO(radu): ideally we would have an estimate of how many rows we actually
 	// have to run the ON condition on.
 	cost += memo.Cost(join.Relational().Stats.RowCount) * cpuCostFactor
+
+	cost += c.colocationCost(
+		join.Left.Relational().OutputCols,
+		join.Right.Relational().OutputCols,
+		math.Min(leftRowCount, rightRowCount),
+	)
+	cost += c.localityAwareMergeJoinCost(join)
 	return cost
 }
 
@@ -353,7 +394,7 @@ func (c *coster) computeIndexJoinCost(join *memo.IndexJoinExpr) memo.Cost {
 	// Since the matching rows in the table may not all be in the same range, this
 	// counts as random I/O.
 	perRowCost := cpuCostFactor + randIOCostFactor +
-		c.rowScanCost(join.Table, cat.PrimaryIndex, join.Cols.Len())
+		c.rowScanCost(join.Table, cat.PrimaryIndex, nil /* zone */, join.Cols.Len())
 	return memo.Cost(leftRowCount) * perRowCost
 }
 
@@ -371,7 +412,7 @@ func (c *coster) computeLookupJoinCost(join *memo.LookupJoinExpr) memo.Cost {
 	// cost of emitting the rows.
 	numLookupCols := join.Cols.Difference(join.Input.Relational().OutputCols).Len()
 	perRowCost := lookupJoinRetrieveRowCost +
-		c.rowScanCost(join.Table, join.Index, numLookupCols)
+		c.rowScanCost(join.Table, join.Index, nil /* zone */, numLookupCols)
 
 	// Add a cost if we have to evaluate an ON condition on every row. The more
 	// leftover conditions, the more expensive it should be. We want to
@@ -400,6 +441,16 @@ func (c *coster) computeLookupJoinCost(join *memo.LookupJoinExpr) memo.Cost {
 	perRowCost += cpuCostFactor * memo.Cost(len(join.On))
 
 	cost += memo.Cost(join.Relational().Stats.RowCount) * perRowCost
+
+	// Every lookup is sent to the lookup table, so each input row is moved if
+	// the tables are not co-located.
+	if !c.zoneConstraintGraph().Empty() {
+		cost += c.tablesColocationCost(
+			c.baseTables(join.Input.Relational().OutputCols),
+			util.MakeFastIntSet(int(join.Table)),
+			leftRowCount,
+		)
+	}
 	return cost
 }
 
@@ -417,8 +468,8 @@ func (c *coster) computeZigzagJoinCost(join *memo.ZigzagJoinExpr) memo.Cost {
 	rightCols := md.TableMeta(join.RightTable).IndexColumns(join.RightIndex)
 	rightCols.IntersectionWith(join.Cols)
 	rightCols.DifferenceWith(leftCols)
-	scanCost := c.rowScanCost(join.LeftTable, join.LeftIndex, leftCols.Len())
-	scanCost += c.rowScanCost(join.RightTable, join.RightIndex, rightCols.Len())
+	scanCost := c.rowScanCost(join.LeftTable, join.LeftIndex, nil /* zone */, leftCols.Len())
+	scanCost += c.rowScanCost(join.RightTable, join.RightIndex, nil /* zone */, rightCols.Len())
 
 	// Double the cost of emitting rows as well as the cost of seeking rows,
 	// given two indexes will be accessed.
@@ -520,75 +571,155 @@ func (c *coster) rowSortCost(numKeyCols int) memo.Cost {
 	return memo.Cost(cost)
 }
 
-// localityMatchScore returns a number from 0.0 to 1.0 that describes how well
-// the current node's locality matches the given zone constraints, with 0.0
-// indicating 0% and 1.0 indicating 100%. In order to match, each successive
-// locality tier must match at least one REQUIRED constraint and not match any
-// PROHIBITED constraints. Locality tiers are hierarchical, so if a locality
-// tier does not match, then tiers after it do not match either. For example:
-//
-//   Locality = [region=us,dc=east]
-//   0.0      = []
-//   0.0      = [+region=eu,+dc=uk]
-//   0.0      = [-region=us]
-//   0.0      = [+region=eu,+dc=east]
-//   0.5      = [+region=us,+dc=west]
-//   0.5      = [+region=us,-dc=east]
-//   1.0      = [+region=us,+dc=east]
-//   1.0      = [+region=us,+dc=east,+rack=1,-ssd]
-//
-// Note that constraints need not be specified in any particular order, so scan
-// all constraints when matching each locality tier.
-func (c *coster) localityMatchScore(zone cat.Zone) float64 {
-	// If there are no replica constraints, then locality can't match.
-	if zone.ReplicaConstraintsCount() == 0 {
-		return 0.0
+// colocationCost is the cost of moving rowCount rows between the inputs of a
+// join, if the zones of the base tables on the two sides pin their data to
+// different localities. Nothing is known about where a table without required
+// constraints is placed, so no cost is added if any table on either side is
+// unconstrained. This makes join orders that first join co-located tables
+// cheaper than otherwise equivalent orders that move data between localities.
+func (c *coster) colocationCost(left, right opt.ColSet, rowCount float64) memo.Cost {
+	// As a fast path, there is nothing to compare if no table in the metadata
+	// is constrained, which is the common case.
+	if c.zoneConstraintGraph().Empty() {
+		return 0
 	}
+	return c.tablesColocationCost(c.baseTables(left), c.baseTables(right), rowCount)
+}
 
-	// matchTier returns true if it can locate a required constraint that matches
-	// the given tier.
-	matchConstraints := func(zc cat.ReplicaConstraints, tier *roachpb.Tier) bool {
-		for i, n := 0, zc.ConstraintCount(); i < n; i++ {
-			con := zc.Constraint(i)
-			if tier.Key == con.GetKey() && tier.Value == con.GetValue() {
-				// If this is a required constraint, then it matches, and no need to
-				// iterate further. If it's prohibited, then it cannot match, so no
-				// need to go further.
-				return con.IsRequired()
-			}
-		}
-		return false
+// tablesColocationCost is like colocationCost, but takes the sets of base
+// tables of the join's inputs.
+func (c *coster) tablesColocationCost(left, right util.FastIntSet, rowCount float64) memo.Cost {
+	if left.Empty() || right.Empty() {
+		return 0
 	}
-
-	// matchReplConstraints returns true if all replica constraints match the
-	// given tier.
-	matchReplConstraints := func(zone cat.Zone, tier *roachpb.Tier) bool {
-		for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
-			replCon := zone.ReplicaConstraints(i)
-			if !matchConstraints(replCon, tier) {
+	md := c.mem.Metadata()
+	g := c.zoneConstraintGraph()
+	constrained := func(tabs util.FastIntSet) bool {
+		for i, ok := tabs.Next(0); ok; i, ok = tabs.Next(i + 1) {
+			if !g.Constrained(md.Table(opt.TableID(i)).ID()) {
 				return false
 			}
 		}
 		return true
 	}
+	if !constrained(left) || !constrained(right) {
+		return 0
+	}
+	for l, ok := left.Next(0); ok; l, ok = left.Next(l + 1) {
+		for r, ok := right.Next(0); ok; r, ok = right.Next(r + 1) {
+			if !g.Colocated(md.Table(opt.TableID(l)).ID(), md.Table(opt.TableID(r)).ID()) {
+				return memo.Cost(rowCount) * latencyCostFactor
+			}
+		}
+	}
+	return 0
+}
+
+// localityAwareMergeJoinCost is the extra cost of a merge join whose inputs are
+// not local to the gateway. A merge join advances both inputs in lockstep, so
+// it waits on a remote input for every batch of rows, whereas a hash join
+// reads its right input once to build the hashtable. The cost is proportional
+// to how far the combined locality score of the inputs is from a perfect
+// match, so a merge join of two local inputs is preferred over a hash join,
+// while a merge join with a remote input is not.
+//
+// The locality score of each input is the lowest score of the primary indexes
+// of its base tables, and the combined score is the product of the scores of
+// the two inputs. Nothing is known about where a table without replica
+// constraints is placed, so no cost is added if any table on either side is
+// unconstrained.
+func (c *coster) localityAwareMergeJoinCost(join *memo.MergeJoinExpr) memo.Cost {
+	if !c.zoneCtx.HasGatewayLocality() {
+		return 0
+	}
+	leftScore, ok := c.inputLocalityScore(join.Left.Relational().OutputCols)
+	if !ok {
+		return 0
+	}
+	rightScore, ok := c.inputLocalityScore(join.Right.Relational().OutputCols)
+	if !ok {
+		return 0
+	}
+	rowCount := join.Left.Relational().Stats.RowCount + join.Right.Relational().Stats.RowCount
+	return memo.Cost(rowCount) * latencyCostFactor * memo.Cost(1.0-leftScore*rightScore)
+}
 
-	// Keep iterating until non-matching tier is found, or all tiers are found to
-	// match.
-	matchCount := 0
-	for i := range c.locality.Tiers {
-		if !matchReplConstraints(zone, &c.locality.Tiers[i]) {
-			break
+// inputLocalityScore returns the lowest locality score of the primary indexes
+// of the base tables of the given columns. It returns ok=false if there are no
+// base tables, or if any of them has no replica constraints.
+func (c *coster) inputLocalityScore(cols opt.ColSet) (float64, bool) {
+	md := c.mem.Metadata()
+	score := 1.0
+	var seen util.FastIntSet
+	for i, ok := cols.Next(0); ok; i, ok = cols.Next(i + 1) {
+		tabID := md.ColumnMeta(opt.ColumnID(i)).Table
+		if tabID == 0 || seen.Contains(int(tabID)) {
+			continue
+		}
+		seen.Add(int(tabID))
+		tab := md.Table(tabID)
+		if tab.IndexCount() == 0 {
+			return 0, false
+		}
+		zone := tab.Index(cat.PrimaryIndex).Zone()
+		if zone.ReplicaConstraintsCount() == 0 {
+			return 0, false
 		}
-		matchCount++
+		score = math.Min(score, cat.LocalityScore(zone, c.zoneCtx.GatewayLocality))
 	}
+	return score, !seen.Empty()
+}
 
-	return float64(matchCount) / float64(len(c.locality.Tiers))
+// baseTables returns the set of the IDs of the base tables of the given
+// columns. Synthesized columns have no base table and are ignored.
+func (c *coster) baseTables(cols opt.ColSet) util.FastIntSet {
+	md := c.mem.Metadata()
+	var tabs util.FastIntSet
+	cols.ForEach(func(i int) {
+		if tabID := md.ColumnMeta(opt.ColumnID(i)).Table; tabID != 0 {
+			tabs.Add(int(tabID))
+		}
+	})
+	return tabs
+}
+
+// zoneConstraintGraph returns the graph of the tables in the memo's metadata,
+// adding any tables that were added to the metadata since the last call.
+func (c *coster) zoneConstraintGraph() *cat.ZoneConstraintGraph {
+	tables := c.mem.Metadata().AllTables()
+	for ; c.zoneGraphTables < len(tables); c.zoneGraphTables++ {
+		// Virtual tables may have no indexes, and so no zone.
+		if tab := tables[c.zoneGraphTables].Table; tab.IndexCount() != 0 {
+			c.zoneGraph.AddTable(tab.ID(), tab.Index(cat.PrimaryIndex).Zone())
+		}
+	}
+	return &c.zoneGraph
+}
+
+// remoteReadAmplification returns a factor of at least 1 by which the latency
+// cost of reading from an index in the given zone is scaled, given the index's
+// locality score. Reads from an index that fully matches the locality, or whose
+// zone has no replica constraints, are not amplified. Otherwise, reads are
+// amplified more if the locality satisfies none of the zone's replica
+// constraint sets, since then no replica can be placed near the gateway.
+func remoteReadAmplification(zone cat.Zone, locality roachpb.Locality, score float64) float64 {
+	if score >= 1 || zone.ReplicaConstraintsCount() == 0 {
+		return 1
+	}
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		if cat.LocalitySatisfiesConstraints(locality, zone.ReplicaConstraints(i)) {
+			return voterReadAmplification
+		}
+	}
+	return nonVoterReadAmplification
 }
 
 // rowScanCost is the CPU cost to scan one row, which depends on the number of
 // columns in the index and (to a lesser extent) on the number of columns we are
-// scanning.
-func (c *coster) rowScanCost(tabID opt.TableID, idxOrd int, numScannedCols int) memo.Cost {
+// scanning. If zone is nil, then the index's zone is fetched from the index.
+func (c *coster) rowScanCost(
+	tabID opt.TableID, idxOrd int, zone cat.Zone, numScannedCols int,
+) memo.Cost {
 	md := c.mem.Metadata()
 	tab := md.Table(tabID)
 	idx := tab.Index(idxOrd)
@@ -597,12 +728,26 @@ func (c *coster) rowScanCost(tabID opt.TableID, idxOrd int, numScannedCols int)
 	// Adjust cost based on how well the current locality matches the index's
 	// zone constraints.
 	var costFactor memo.Cost = cpuCostFactor
-	if len(c.locality.Tiers) != 0 {
+	if c.zoneCtx.HasGatewayLocality() {
 		// If 0% of locality tiers have matching constraints, then add additional
 		// cost. If 100% of locality tiers have matching constraints, then add no
 		// additional cost. Anything in between is proportional to the number of
 		// matches.
-		costFactor += latencyCostFactor * memo.Cost(1.0-c.localityMatchScore(idx.Zone()))
+		if zone == nil {
+			zone = idx.Zone()
+		}
+		// As a fast path, if the most preferred leaseholders are in the current
+		// locality, then reads are expected to be served locally, whatever the
+		// replica constraints are.
+		if !cat.LeasePreferenceMatchesLocality(zone, c.zoneCtx.GatewayLocality) {
+			latencyFactor := memo.Cost(latencyCostFactor)
+			if cat.IsPinned(zone) {
+				latencyFactor = pinnedLatencyCostFactor
+			}
+			score := cat.LocalityScore(zone, c.zoneCtx.GatewayLocality)
+			amplification := memo.Cost(remoteReadAmplification(zone, c.zoneCtx.GatewayLocality, score))
+			costFactor += latencyFactor * amplification * memo.Cost(1.0-score)
+		}
 	}
 
 	// The number of the columns in the index matter because more columns means
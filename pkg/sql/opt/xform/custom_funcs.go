@@ -86,6 +86,7 @@ func (c *CustomFuncs) GenerateIndexScans(grp memo.RelExpr, scanPrivate *memo.Sca
 		if iter.isCovering() {
 			scan := memo.ScanExpr{ScanPrivate: *scanPrivate}
 			scan.Index = iter.indexOrdinal
+			scan.Zone = iter.index.Zone()
 			c.e.mem.AddScanToGroup(&scan, grp)
 			continue
 		}
@@ -105,6 +106,7 @@ func (c *CustomFuncs) GenerateIndexScans(grp memo.RelExpr, scanPrivate *memo.Sca
 		// the PK columns.
 		newScanPrivate := *scanPrivate
 		newScanPrivate.Index = iter.indexOrdinal
+		newScanPrivate.Zone = iter.index.Zone()
 		newScanPrivate.Cols = iter.indexCols().Intersection(scanPrivate.Cols)
 		newScanPrivate.Cols.UnionWith(sb.primaryKeyCols())
 		sb.setScan(&newScanPrivate)
@@ -199,6 +201,7 @@ func (c *CustomFuncs) GenerateConstrainedScans(
 		// Construct new constrained ScanPrivate.
 		newScanPrivate := *scanPrivate
 		newScanPrivate.Index = iter.indexOrdinal
+		newScanPrivate.Zone = iter.index.Zone()
 		newScanPrivate.Constraint = constraint
 
 		// If the alternate index includes the set of needed columns, then construct
@@ -273,6 +276,7 @@ func (c *CustomFuncs) GenerateInvertedIndexScans(
 		// Construct new ScanOpDef with the new index and constraint.
 		newScanPrivate := *scanPrivate
 		newScanPrivate.Index = iter.indexOrdinal
+		newScanPrivate.Zone = iter.index.Zone()
 		newScanPrivate.Constraint = constraint
 
 		// Though the index is marked as containing the JSONB column being
@@ -499,6 +503,7 @@ func (c *CustomFuncs) GenerateLimitedScans(
 	for iter.next() {
 		newScanPrivate := *scanPrivate
 		newScanPrivate.Index = iter.indexOrdinal
+		newScanPrivate.Zone = iter.index.Zone()
 
 		// If the alternate index does not conform to the ordering, then skip it.
 		// If reverse=true, then the scan needs to be in reverse order to match
@@ -18,6 +18,7 @@ import (
 	"math/rand"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/ordering"
@@ -54,6 +55,11 @@ type RuleSet = util.FastIntSet
 type Optimizer struct {
 	evalCtx *tree.EvalContext
 
+	// zoneCtx is the context in which zone constraints are evaluated against
+	// the gateway. It is derived from evalCtx unless overridden by calling
+	// SetZoneConstraintEvalContext.
+	zoneCtx cat.ZoneConstraintEvalContext
+
 	// f is the factory that creates the normalized expressions during the first
 	// optimization phase.
 	f norm.Factory
@@ -98,10 +104,11 @@ type Optimizer struct {
 // must be called before the optimizer can be used (or reused).
 func (o *Optimizer) Init(evalCtx *tree.EvalContext) {
 	o.evalCtx = evalCtx
+	o.zoneCtx = cat.ZoneConstraintEvalContext{GatewayLocality: evalCtx.Locality}
 	o.f.Init(evalCtx)
 	o.mem = o.f.Memo()
 	o.explorer.init(o)
-	o.defaultCoster.Init(evalCtx, o.mem, evalCtx.TestingKnobs.OptimizerCostPerturbation)
+	o.defaultCoster.Init(o.zoneCtx, o.mem, evalCtx.TestingKnobs.OptimizerCostPerturbation)
 	o.coster = &o.defaultCoster
 	o.stateMap = make(map[groupStateKey]*groupState)
 	o.matchedRule = nil
@@ -116,10 +123,27 @@ func (o *Optimizer) Init(evalCtx *tree.EvalContext) {
 // used to extract a read-only memo during the PREPARE phase.
 func (o *Optimizer) DetachMemo() *memo.Memo {
 	detach := o.f.DetachMemo()
+	zoneCtx := o.zoneCtx
 	o.Init(o.evalCtx)
+	o.SetZoneConstraintEvalContext(zoneCtx)
 	return detach
 }
 
+// ZoneConstraintEvalContext returns the context in which the optimizer
+// evaluates zone constraints against the gateway.
+func (o *Optimizer) ZoneConstraintEvalContext() *cat.ZoneConstraintEvalContext {
+	return &o.zoneCtx
+}
+
+// SetZoneConstraintEvalContext overrides the context in which the optimizer
+// evaluates zone constraints against the gateway, which by default only holds
+// the locality of the EvalContext given to Init. It must be called after Init
+// and before the optimizer is used.
+func (o *Optimizer) SetZoneConstraintEvalContext(zoneCtx cat.ZoneConstraintEvalContext) {
+	o.zoneCtx = zoneCtx
+	o.defaultCoster.zoneCtx = zoneCtx
+}
+
 // Factory returns a factory interface that the caller uses to construct an
 // input expression tree. The root of the resulting tree can be passed to the
 // Optimize method in order to find the lowest cost plan.
@@ -895,7 +919,7 @@ func (o *Optimizer) FormatMemo(flags FmtFlags) string {
 // the real computed cost, not the perturbed cost.
 func (o *Optimizer) RecomputeCost() {
 	var c coster
-	c.Init(o.evalCtx, o.mem, 0 /* perturbation */)
+	c.Init(o.zoneCtx, o.mem, 0 /* perturbation */)
 
 	root := o.mem.RootExpr()
 	rootProps := o.mem.RootProps()
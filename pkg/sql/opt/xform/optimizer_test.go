@@ -16,12 +16,15 @@ package xform_test
 
 import (
 	"flag"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils"
@@ -130,6 +133,30 @@ func TestDetachMemoRace(t *testing.T) {
 	wg.Wait()
 }
 
+func TestZoneConstraintEvalContext(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var o xform.Optimizer
+	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	evalCtx.Locality = roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: "us-east"}}}
+	o.Init(&evalCtx)
+	if got := o.ZoneConstraintEvalContext().GatewayLocality; !reflect.DeepEqual(got, evalCtx.Locality) {
+		t.Errorf("expected the gateway locality of the EvalContext, got %s", got)
+	}
+
+	west := roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: "us-west"}}}
+	o.SetZoneConstraintEvalContext(cat.ZoneConstraintEvalContext{GatewayLocality: west})
+	o.DetachMemo()
+	if got := o.ZoneConstraintEvalContext().GatewayLocality; !reflect.DeepEqual(got, west) {
+		t.Errorf("expected DetachMemo to keep the overridden gateway locality, got %s", got)
+	}
+
+	o.Init(&evalCtx)
+	if got := o.ZoneConstraintEvalContext().GatewayLocality; !reflect.DeepEqual(got, evalCtx.Locality) {
+		t.Errorf("expected Init to reset the gateway locality, got %s", got)
+	}
+}
+
 // TestCoster files can be run separately like this:
 //   make test PKG=./pkg/sql/opt/xform TESTS="TestCoster/sort"
 //   make test PKG=./pkg/sql/opt/xform TESTS="TestCoster/scan"
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 )
 
 // optCatalog implements the cat.Catalog interface over the SchemaResolver
@@ -38,9 +39,19 @@ type optCatalog struct {
 	planner *planner
 
 	// cfg is the gossiped and cached system config. It may be nil if the node
-	// does not yet have it available.
+	// does not yet have it available. Gossip replaces the system config rather
+	// than modifying it, so every zone read while planning a query comes from
+	// the same point-in-time view, even if zones change concurrently.
 	cfg *config.SystemConfig
 
+	// zones resolves the effective zones of tables and indexes from cfg. It is
+	// nil if cfg is.
+	zones *config.ZoneInheritanceResolver
+
+	// zoneConfigVersion is the version of the zone configs in cfg, or possibly
+	// an older version. See ZoneConfigVersion.
+	zoneConfigVersion uint64
+
 	// dataSources is a cache of table and view objects that's used to satisfy
 	// repeated calls for the same data source.
 	// Note that the data source object might still need to be recreated if
@@ -70,10 +81,18 @@ func (oc *optCatalog) reset() {
 		oc.dataSources = make(map[*sqlbase.ImmutableTableDescriptor]cat.DataSource)
 	}
 
+	// Read the zone config version before the system config, so that the
+	// version is never newer than the zones in the system config.
+	oc.zoneConfigVersion = oc.planner.execCfg.ZoneConfigVersion.Get()
+
 	// Gossip can be nil in testing scenarios.
 	if oc.planner.execCfg.Gossip != nil {
 		oc.cfg = oc.planner.execCfg.Gossip.GetSystemConfig()
 	}
+	oc.zones = nil
+	if oc.cfg != nil {
+		oc.zones = config.NewZoneInheritanceResolver(oc.cfg)
+	}
 }
 
 // optSchema is a wrapper around sqlbase.DatabaseDescriptor that implements the
@@ -171,6 +190,51 @@ func (oc *optCatalog) ResolveDataSourceByID(
 	return oc.dataSourceForDesc(ctx, desc, &name)
 }
 
+// ZoneConfigVersion is part of the cat.Catalog interface.
+func (oc *optCatalog) ZoneConfigVersion() uint64 {
+	return oc.zoneConfigVersion
+}
+
+// TablesWithZoneConfig is part of the cat.Catalog interface.
+func (oc *optCatalog) TablesWithZoneConfig(
+	ctx context.Context, dbName string,
+) ([]cat.Table, error) {
+	dbDesc, err := oc.planner.ResolveUncachedDatabaseByName(ctx, dbName, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := oc.planner.getAllNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Zones are keyed by the ID of the object they apply to, and the IDs of the
+	// tables in a database are not contiguous, so every zone is scanned and
+	// filtered by the parent of its object.
+	kvs, err := oc.planner.Txn().Scan(ctx, zonesTablePrefix, zonesTablePrefix.PrefixEnd(), 0 /* maxRows */)
+	if err != nil {
+		return nil, err
+	}
+	var tables []cat.Table
+	for i := range kvs {
+		_, id, err := encoding.DecodeUvarintAscending(kvs[i].Key[len(zonesTablePrefix):])
+		if err != nil {
+			return nil, err
+		}
+		if entry, ok := namespace[sqlbase.ID(id)]; !ok || entry.parentID != dbDesc.ID {
+			continue
+		}
+		ds, err := oc.ResolveDataSourceByID(ctx, cat.StableID(id))
+		if err != nil {
+			return nil, err
+		}
+		if tab, ok := ds.(cat.Table); ok {
+			tables = append(tables, tab)
+		}
+	}
+	return tables, nil
+}
+
 // CheckPrivilege is part of the cat.Catalog interface.
 func (oc *optCatalog) CheckPrivilege(ctx context.Context, o cat.Object, priv privilege.Kind) error {
 	switch t := o.(type) {
@@ -199,7 +263,10 @@ func (oc *optCatalog) dataSourceForDesc(
 
 	ds, ok := oc.dataSources[desc]
 	if ok {
-		return ds, nil
+		seq, isSeq := ds.(*optSequence)
+		if !isSeq || !seq.isStale(oc) {
+			return ds, nil
+		}
 	}
 
 	switch {
@@ -207,7 +274,11 @@ func (oc *optCatalog) dataSourceForDesc(
 		ds = newOptView(desc, name)
 
 	case desc.IsSequence():
-		ds = newOptSequence(desc, name)
+		zoneConfig, err := oc.getZoneConfig(desc)
+		if err != nil {
+			return nil, err
+		}
+		ds = newOptSequence(desc, name, zoneConfig)
 
 	default:
 		return nil, pgerror.NewAssertionErrorf("unexpected table descriptor: %+v", desc)
@@ -280,7 +351,10 @@ func (oc *optCatalog) dataSourceForTable(
 		}
 	}
 
-	ds := newOptTable(desc, id, name, tableStats, zoneConfig)
+	ds, err := newOptTable(desc, id, name, tableStats, zoneConfig, oc.zones)
+	if err != nil {
+		return nil, err
+	}
 	if !desc.IsVirtualTable() {
 		// Virtual tables can have multiple effective instances that utilize the
 		// same descriptor (see above).
@@ -301,10 +375,10 @@ func (oc *optCatalog) getZoneConfig(
 	// Lookup table's zone if system config is available (it may not be as node
 	// is starting up and before it's received the gossiped config). If it is
 	// not available, use an empty config that has no zone constraints.
-	if oc.cfg == nil || desc.IsVirtualTable() {
+	if oc.zones == nil || desc.IsVirtualTable() {
 		return emptyZoneConfig, nil
 	}
-	zone, err := oc.cfg.GetZoneConfigForObject(uint32(desc.ID))
+	zone, err := oc.zones.ResolveEffectiveZone(uint32(desc.ID), 0 /* indexID */, "" /* partitionName */)
 	if err != nil {
 		return nil, err
 	}
@@ -379,13 +453,18 @@ type optSequence struct {
 	// name is the fully qualified, fully resolved, fully normalized name of the
 	// sequence.
 	name cat.DataSourceName
+
+	// zone is the zone config of the sequence's range.
+	zone *config.ZoneConfig
 }
 
 var _ cat.DataSource = &optSequence{}
 var _ cat.Sequence = &optSequence{}
 
-func newOptSequence(desc *sqlbase.ImmutableTableDescriptor, name *cat.DataSourceName) *optSequence {
-	os := &optSequence{desc: desc, name: *name}
+func newOptSequence(
+	desc *sqlbase.ImmutableTableDescriptor, name *cat.DataSourceName, zone *config.ZoneConfig,
+) *optSequence {
+	os := &optSequence{desc: desc, name: *name, zone: zone}
 
 	// The cat.Sequence interface requires that table names be fully qualified.
 	os.name.ExplicitSchema = true
@@ -405,7 +484,15 @@ func (os *optSequence) Equals(other cat.Object) bool {
 	if !ok {
 		return false
 	}
-	return os.desc.ID == otherSeq.desc.ID && os.desc.Version == otherSeq.desc.Version
+	return os.desc.ID == otherSeq.desc.ID && os.desc.Version == otherSeq.desc.Version &&
+		zonesAreEqual(os.zone, otherSeq.zone)
+}
+
+// isStale checks if the optSequence object needs to be refreshed because its
+// zone config has changed. False positives are ok.
+func (os *optSequence) isStale(oc *optCatalog) bool {
+	zone, err := oc.getZoneConfig(os.desc)
+	return err != nil || !zonesAreEqual(zone, os.zone)
 }
 
 // Name is part of the cat.DataSource interface.
@@ -418,6 +505,11 @@ func (os *optSequence) SequenceName() *tree.TableName {
 	return os.Name()
 }
 
+// Zone is part of the cat.Sequence interface.
+func (os *optSequence) Zone() cat.Zone {
+	return os.zone
+}
+
 // optTable is a wrapper around sqlbase.ImmutableTableDescriptor that caches
 // index wrappers and maintains a ColumnID => Column mapping for fast lookup.
 type optTable struct {
@@ -467,7 +559,8 @@ func newOptTable(
 	name *cat.DataSourceName,
 	stats []*stats.TableStatistic,
 	tblZone *config.ZoneConfig,
-) *optTable {
+	zones *config.ZoneInheritanceResolver,
+) (*optTable, error) {
 	ot := &optTable{
 		desc:     desc,
 		id:       id,
@@ -499,14 +592,17 @@ func newOptTable(
 				idxDesc = &ot.desc.DeletableIndexes()[i-1]
 			}
 
-			// If there is a subzone that applies to the entire index, use that,
-			// else use the table zone. Skip subzones that apply to partitions,
-			// since they apply only to a subset of the index.
+			// Use the effective zone of the entire index, which inherits from the
+			// table zone. Skip subzones that apply to partitions, since they
+			// apply only to a subset of the index.
 			idxZone := tblZone
-			for j := range tblZone.Subzones {
-				subzone := &tblZone.Subzones[j]
-				if subzone.IndexID == uint32(idxDesc.ID) && subzone.PartitionName == "" {
-					idxZone = &subzone.Config
+			if zones != nil {
+				zone, err := zones.ResolveEffectiveZone(uint32(desc.ID), uint32(idxDesc.ID), "")
+				if err != nil {
+					return nil, err
+				}
+				if zone != nil {
+					idxZone = zone
 				}
 			}
 
@@ -544,7 +640,7 @@ func newOptTable(
 		ot.stats = ot.stats[:n]
 	}
 
-	return ot
+	return ot, nil
 }
 
 // ID is part of the cat.Object interface.
@@ -1018,7 +1114,8 @@ func (oi *optFamily) Table() cat.Table {
 }
 
 // zonesAreEqual compares two zones for equality. Note that only fields actually
-// exposed by the cat.Zone interface and needed by the optimizer are compared.
+// exposed by the cat.Zone interface and needed by the optimizer are compared,
+// and that zones which differ only in the order of their constraints are equal.
 func zonesAreEqual(left, right *config.ZoneConfig) bool {
 	if left == right {
 		return true
@@ -1047,29 +1144,5 @@ func zonesAreEqual(left, right *config.ZoneConfig) bool {
 		return zonesAreEqual(&leftSubzone.Config, &rightSubzone.Config)
 	}
 
-	for i := range left.Constraints {
-		leftReplCons := &left.Constraints[i]
-		rightReplCons := &right.Constraints[i]
-		if leftReplCons.NumReplicas != rightReplCons.NumReplicas {
-			return false
-		}
-		if len(leftReplCons.Constraints) != len(rightReplCons.Constraints) {
-			return false
-		}
-
-		for j := range leftReplCons.Constraints {
-			leftCons := &leftReplCons.Constraints[j]
-			rightCons := &rightReplCons.Constraints[j]
-			if leftCons.Type != rightCons.Type {
-				return false
-			}
-			if leftCons.Key != rightCons.Key {
-				return false
-			}
-			if leftCons.Value != rightCons.Value {
-				return false
-			}
-		}
-	}
-	return true
+	return cat.ZoneCompare(cat.NormalizeZone(left), cat.NormalizeZone(right)) == 0
 }
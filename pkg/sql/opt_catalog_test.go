@@ -1,4 +1,4 @@
-// Copyright 2015 The Cockroach Authors.
+// Copyright 2019 The Cockroach Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,87 +15,110 @@
 package sql
 
 import (
+	"context"
 	"testing"
 
-	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
-	"github.com/stretchr/testify/require"
+	"github.com/pkg/errors"
 )
 
-// Note that zonesAreEqual only tests equality on fields used by the optimizer.
-func TestZonesAreEqual(t *testing.T) {
+// TestOptCatalogIndexZones verifies that the optimizer catalog exposes the
+// zone configs stored in a real cluster through the cat.Zone interface.
+func TestOptCatalogIndexZones(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
-	zone1 := &config.ZoneConfig{}
-	require.Equal(t, zone1, zone1)
-
-	zone2 := &config.ZoneConfig{}
-	require.Equal(t, zone1, zone2)
-
-	constraints1 := []config.Constraints{
-		{NumReplicas: 0, Constraints: []config.Constraint{
-			{Type: config.Constraint_REQUIRED, Key: "k1", Value: "v1"},
-		}},
-	}
-	zone3 := &config.ZoneConfig{Constraints: constraints1}
-	zone4 := &config.ZoneConfig{Constraints: constraints1}
-	require.Equal(t, zone3, zone4)
-
-	zone5 := &config.ZoneConfig{Constraints: []config.Constraints{
-		{NumReplicas: 3, Constraints: []config.Constraint{
-			{Type: config.Constraint_REQUIRED, Key: "k1", Value: "v1"},
-		}},
-	}}
-	require.NotEqual(t, zone4, zone5)
-
-	constraints2 := []config.Constraints{
-		{NumReplicas: 3, Constraints: []config.Constraint{
-			{Type: config.Constraint_REQUIRED, Key: "k1", Value: "v1"},
+	ctx := context.Background()
+	s, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{
+		Locality: roachpb.Locality{Tiers: []roachpb.Tier{
+			{Key: "region", Value: "east"},
+			{Key: "dc", Value: "dc1"},
 		}},
-		{NumReplicas: 1, Constraints: []config.Constraint{
-			{Type: config.Constraint_REQUIRED, Key: "k2", Value: "v2"},
-		}},
-	}
-	zone6 := &config.ZoneConfig{Constraints: constraints2}
-	require.NotEqual(t, zone5, zone6)
-
-	zone7 := &config.ZoneConfig{Constraints: []config.Constraints{
-		{NumReplicas: 3, Constraints: []config.Constraint{
-			{Type: config.Constraint_REQUIRED, Key: "k1", Value: "v1"},
-		}},
-		{NumReplicas: 1, Constraints: []config.Constraint{
-			{Type: config.Constraint_REQUIRED, Key: "k3", Value: "v3"},
-		}},
-	}}
-	require.NotEqual(t, zone6, zone7)
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
 
-	zone8 := &config.ZoneConfig{Constraints: []config.Constraints{
-		{NumReplicas: 3, Constraints: []config.Constraint{
-			{Type: config.Constraint_REQUIRED, Key: "k1", Value: "v1"},
-		}},
-		{NumReplicas: 1, Constraints: []config.Constraint{
-			{Type: config.Constraint_PROHIBITED, Key: "k2", Value: "v2"},
-		}},
-	}}
-	require.NotEqual(t, zone6, zone8)
+	sqlDB.Exec(t, `
+		CREATE DATABASE t;
+		CREATE TABLE t.kv (k INT PRIMARY KEY, v INT, INDEX secondary (v));
+		ALTER TABLE t.kv CONFIGURE ZONE USING
+			num_replicas = 3,
+			constraints = '{"+region=east": 2, "+dc=dc1": 1}',
+			lease_preferences = '[[+region=east, -dc=dc2]]';
+	`)
 
-	subzones1 := []config.Subzone{{IndexID: 0, Config: *zone3}}
-	zone9 := &config.ZoneConfig{Constraints: constraints1, Subzones: subzones1}
-	require.NotEqual(t, zone3, zone9)
+	execCfg := s.ExecutorConfig().(ExecutorConfig)
+	name := tree.MakeTableName("t", "kv")
 
-	zone10 := &config.ZoneConfig{Constraints: constraints1, Subzones: []config.Subzone{
-		{IndexID: 1, Config: *zone3},
-	}}
-	require.NotEqual(t, zone9, zone10)
+	// Zone configs reach the catalog via gossip, so retry until they show up.
+	testutils.SucceedsSoon(t, func() error {
+		txn := client.NewTxn(ctx, kvDB, s.NodeID(), client.RootTxn)
+		p, cleanup := newInternalPlanner("test", txn, security.RootUser, &MemoryMetrics{}, &execCfg)
+		defer cleanup()
+		p.extendedEvalCtx.Tables.leaseMgr = s.LeaseManager().(*LeaseManager)
 
-	zone11 := &config.ZoneConfig{Constraints: constraints1, Subzones: []config.Subzone{
-		{IndexID: 1, PartitionName: "p1", Config: *zone3},
-	}}
-	require.NotEqual(t, zone10, zone11)
+		var oc optCatalog
+		oc.init(p)
+		oc.reset()
+		ds, _, err := oc.ResolveDataSource(ctx, &name)
+		if err != nil {
+			return err
+		}
+		tab := ds.(cat.Table)
 
-	zone12 := &config.ZoneConfig{Constraints: constraints1, Subzones: []config.Subzone{
-		{IndexID: 1, Config: *zone3},
-		{IndexID: 2, PartitionName: "p1", Config: *zone3},
-	}}
-	require.NotEqual(t, zone10, zone12)
+		// Index zones are only supported in CCL builds, so every index uses the
+		// table zone.
+		for i, n := 0, tab.IndexCount(); i < n; i++ {
+			idx := tab.Index(i)
+			zone := idx.Zone()
+			if zone.ReplicaCount() != 3 {
+				return errors.Errorf("index %s: expected 3 replicas, got %d", idx.Name(), zone.ReplicaCount())
+			}
+			if zone.ReplicaConstraintsCount() != 2 {
+				return errors.Errorf("index %s: expected 2 replica constraint sets, got %d",
+					idx.Name(), zone.ReplicaConstraintsCount())
+			}
+			for j, expected := range []struct {
+				replicas int32
+				key      string
+				value    string
+			}{
+				{replicas: 1, key: "dc", value: "dc1"},
+				{replicas: 2, key: "region", value: "east"},
+			} {
+				replConstraint := zone.ReplicaConstraints(j)
+				if replConstraint.ReplicaCount() != expected.replicas {
+					return errors.Errorf("index %s, set %d: expected %d replicas, got %d",
+						idx.Name(), j, expected.replicas, replConstraint.ReplicaCount())
+				}
+				if replConstraint.ConstraintCount() != 1 {
+					return errors.Errorf("index %s, set %d: expected 1 constraint, got %d",
+						idx.Name(), j, replConstraint.ConstraintCount())
+				}
+				c := replConstraint.Constraint(0)
+				if !c.IsRequired() || c.GetKey() != expected.key || c.GetValue() != expected.value {
+					return errors.Errorf("index %s, set %d: expected +%s=%s, got %v",
+						idx.Name(), j, expected.key, expected.value, c)
+				}
+			}
+			if zone.LeasePreferenceCount() != 1 {
+				return errors.Errorf("index %s: expected 1 lease preference, got %d",
+					idx.Name(), zone.LeasePreferenceCount())
+			}
+			leasePref := zone.LeasePreference(0)
+			if leasePref.ConstraintCount() != 2 ||
+				!leasePref.Constraint(0).IsRequired() || leasePref.Constraint(1).IsRequired() {
+				return errors.Errorf("index %s: unexpected lease preference", idx.Name())
+			}
+		}
+		return nil
+	})
 }
@@ -92,6 +92,7 @@ func (ef *execFactory) ConstructScan(
 	if err := scan.initTable(context.TODO(), ef.planner, tabDesc, nil, colCfg); err != nil {
 		return nil, err
 	}
+	ef.planner.noteIndexRead(table, index)
 
 	if indexConstraint != nil && indexConstraint.IsContradiction() {
 		return newZeroNode(scan.resultColumns), nil
@@ -120,6 +121,7 @@ func (ef *execFactory) ConstructScan(
 	}
 	scan.props.ordering = sqlbase.ColumnOrdering(reqOrdering)
 	scan.createdByOpt = true
+	scan.zone = index.Zone()
 	return scan, nil
 }
 
@@ -614,6 +616,7 @@ func (ef *execFactory) ConstructLookupJoin(
 	if err := tableScan.initTable(context.TODO(), ef.planner, tabDesc, nil, colCfg); err != nil {
 		return nil, err
 	}
+	ef.planner.noteIndexRead(table, index)
 
 	tableScan.index = indexDesc
 	tableScan.isSecondaryIndex = (indexDesc != &tabDesc.PrimaryIndex)
@@ -456,6 +456,8 @@ func TestParse(t *testing.T) {
 		{`EXPLAIN SHOW ZONE CONFIGURATIONS`},
 		{`SHOW ZONE CONFIGURATION FOR RANGE default`},
 		{`SHOW ZONE CONFIGURATION FOR RANGE meta`},
+		{`SHOW ZONE CONFIGURATION FOR RANGE '/Table/53' TO '/Table/54'`},
+		{`SHOW ZONE CONFIGURATION FOR RANGE b'\xbd' TO b'\xbe'`},
 		{`SHOW ZONE CONFIGURATION FOR DATABASE db`},
 		{`SHOW ZONE CONFIGURATION FOR TABLE db.t`},
 		{`SHOW ZONE CONFIGURATION FOR PARTITION p OF TABLE db.t`},
@@ -465,6 +467,13 @@ func TestParse(t *testing.T) {
 		{`SHOW ZONE CONFIGURATION FOR INDEX t@i`},
 		{`SHOW ZONE CONFIGURATION FOR INDEX i`},
 
+		{`EXPLAIN ZONE CONFIGURATION FOR RANGE default`},
+		{`EXPLAIN ZONE CONFIGURATION FOR DATABASE db`},
+		{`EXPLAIN ZONE CONFIGURATION FOR TABLE db.t`},
+		{`EXPLAIN ZONE CONFIGURATION FOR PARTITION p OF TABLE t`},
+		{`EXPLAIN ZONE CONFIGURATION FOR INDEX t@i`},
+		{`EXPLAIN (VERBOSE) ZONE CONFIGURATION FOR TABLE db.t`},
+
 		// Tables are the default, but can also be specified with
 		// GRANT x ON TABLE y. However, the stringer does not output TABLE.
 		{`SHOW GRANTS`},
@@ -1157,6 +1166,9 @@ func TestParse(t *testing.T) {
 		{`ALTER INDEX i CONFIGURE ZONE USING foo = COPY FROM PARENT`},
 		{`ALTER INDEX i CONFIGURE ZONE USING foo = bar, baz = COPY FROM PARENT`},
 		{`ALTER INDEX i CONFIGURE ZONE USING foo = COPY FROM PARENT, baz = COPY FROM PARENT`},
+		{`ALTER TABLE t CONFIGURE ZONE USING TEMPLATE 'foo'`},
+		{`ALTER TABLE t CONFIGURE ZONE USING TEMPLATE 'foo' ('bar', 'baz')`},
+		{`ALTER INDEX t@i CONFIGURE ZONE USING TEMPLATE $1 ($2)`},
 		{`ALTER INDEX i CONFIGURE ZONE USING foo = bar, other = COPY FROM PARENT, baz = yay`},
 
 		{`ALTER RANGE default CONFIGURE ZONE DISCARD`},
@@ -2137,6 +2149,10 @@ HINT: try \h CREATE TABLE`},
 CREATE TABLE test (
   foo BIT(0)
            ^
+`},
+		{`EXPLAIN (OPT) ZONE CONFIGURATION FOR TABLE t`, `syntax error: unsupported EXPLAIN ZONE CONFIGURATION option: opt at or near "EOF"
+EXPLAIN (OPT) ZONE CONFIGURATION FOR TABLE t
+                                            ^
 `},
 		{`CREATE TABLE test (
   foo INT8 DEFAULT 1 DEFAULT 2
@@ -396,6 +396,9 @@ func (u *sqlSymUnion) rangePartitions() []tree.RangePartition {
 func (u *sqlSymUnion) setZoneConfig() *tree.SetZoneConfig {
     return u.val.(*tree.SetZoneConfig)
 }
+func (u *sqlSymUnion) zoneSpecifier() tree.ZoneSpecifier {
+    return u.val.(tree.ZoneSpecifier)
+}
 func (u *sqlSymUnion) tuples() []*tree.Tuple {
     return u.val.([]*tree.Tuple)
 }
@@ -731,6 +734,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> show_transaction_stmt
 %type <tree.Statement> show_users_stmt
 %type <tree.Statement> show_zone_stmt
+%type <tree.ZoneSpecifier> zone_specifier
+%type <tree.Expr> zone_range_key
 
 %type <str> session_var
 %type <*string> comment_text
@@ -1117,6 +1122,7 @@ alter_ddl_stmt:
 //   DISCARD
 //   USING <var> = <expr> [, ...]
 //   USING <var> = COPY FROM PARENT [, ...]
+//   USING TEMPLATE <template> [( <region> [, ...] )]
 //   { TO | = } <expr>
 //
 // %SeeAlso: WEBDOCS/alter-table.html
@@ -1204,6 +1210,7 @@ alter_database_stmt:
 //   DISCARD
 //   USING <var> = <expr> [, ...]
 //   USING <var> = COPY FROM PARENT [, ...]
+//   USING TEMPLATE <template> [( <region> [, ...] )]
 //   { TO | = } <expr>
 //
 // %SeeAlso: ALTER TABLE
@@ -1326,6 +1333,14 @@ set_zone_config:
     /* SKIP DOC */
     $$.val = &tree.SetZoneConfig{SetDefault: true}
   }
+| CONFIGURE ZONE USING TEMPLATE string_or_placeholder
+  {
+    $$.val = &tree.SetZoneConfig{Template: $5.expr()}
+  }
+| CONFIGURE ZONE USING TEMPLATE string_or_placeholder '(' string_or_placeholder_list ')'
+  {
+    $$.val = &tree.SetZoneConfig{Template: $5.expr(), TemplateRegions: $7.exprs()}
+  }
 | CONFIGURE ZONE DISCARD
   {
     $$.val = &tree.SetZoneConfig{YAMLConfig: tree.DNull}
@@ -2368,6 +2383,12 @@ table_name_list:
 // EXPLAIN ([PLAN ,] <planoptions...> ) <statement>
 // EXPLAIN [ANALYZE] (DISTSQL) <statement>
 // EXPLAIN ANALYZE [(DISTSQL)] <statement>
+// EXPLAIN ZONE CONFIGURATION FOR RANGE <zone_name>
+// EXPLAIN ZONE CONFIGURATION FOR DATABASE <database_name>
+// EXPLAIN ZONE CONFIGURATION FOR TABLE <tablename>
+// EXPLAIN ZONE CONFIGURATION FOR PARTITION <partition_name> OF TABLE <tablename>
+// EXPLAIN ZONE CONFIGURATION FOR INDEX <tablename>@<indexname>
+// EXPLAIN (VERBOSE) ZONE CONFIGURATION FOR <zone_specifier>
 //
 // Explainable statements:
 //     SELECT, CREATE, DROP, ALTER, INSERT, UPSERT, UPDATE, DELETE,
@@ -2395,6 +2416,20 @@ explain_stmt:
   {
     $$.val = &tree.Explain{Options: append($4.strs(), $2), Statement: $6.stmt()}
   }
+| EXPLAIN ZONE CONFIGURATION FOR zone_specifier
+  {
+    $$.val = &tree.ExplainZoneConfig{ZoneSpecifier: $5.zoneSpecifier()}
+  }
+| EXPLAIN '(' explain_option_list ')' ZONE CONFIGURATION FOR zone_specifier
+  {
+    for _, opt := range $3.strs() {
+      if strings.ToLower(opt) != "verbose" {
+        sqllex.Error(fmt.Sprintf("unsupported EXPLAIN ZONE CONFIGURATION option: %s", opt))
+        return 1
+      }
+    }
+    $$.val = &tree.ExplainZoneConfig{ZoneSpecifier: $8.zoneSpecifier(), Verbose: true}
+  }
 // This second error rule is necessary, because otherwise
 // preparable_stmt also provides "selectclause := '(' error ..." and
 // cause a help text for the select clause, which will be confusing in
@@ -3484,42 +3519,65 @@ show_roles_stmt:
 | SHOW ROLES error // SHOW HELP: SHOW ROLES
 
 show_zone_stmt:
-  SHOW ZONE CONFIGURATION FOR RANGE zone_name
+  SHOW ZONE CONFIGURATION FOR zone_specifier
   {
-    $$.val = &tree.ShowZoneConfig{ZoneSpecifier: tree.ZoneSpecifier{NamedZone: tree.UnrestrictedName($6)}}
+    $$.val = &tree.ShowZoneConfig{ZoneSpecifier: $5.zoneSpecifier()}
   }
-| SHOW ZONE CONFIGURATION FOR DATABASE database_name
+| SHOW ZONE CONFIGURATION FOR RANGE zone_range_key TO zone_range_key
   {
-    $$.val = &tree.ShowZoneConfig{ZoneSpecifier: tree.ZoneSpecifier{Database: tree.Name($6)}}
+    $$.val = &tree.ShowZoneConfig{StartKey: $6.expr(), EndKey: $8.expr()}
   }
-| SHOW ZONE CONFIGURATION FOR TABLE table_name opt_partition
+| SHOW ZONE CONFIGURATIONS
   {
-    name := $6.unresolvedObjectName().ToTableName()
-    $$.val = &tree.ShowZoneConfig{ZoneSpecifier: tree.ZoneSpecifier{
-        TableOrIndex: tree.TableIndexName{Table: name},
-    }}
+    $$.val = &tree.ShowZoneConfig{}
+  }
+| SHOW ALL ZONE CONFIGURATIONS
+  {
+    $$.val = &tree.ShowZoneConfig{}
   }
-| SHOW ZONE CONFIGURATION FOR PARTITION partition_name OF TABLE table_name
+
+zone_specifier:
+  RANGE zone_name
   {
-    name := $9.unresolvedObjectName().ToTableName()
-    $$.val = &tree.ShowZoneConfig{ZoneSpecifier: tree.ZoneSpecifier{
+    $$.val = tree.ZoneSpecifier{NamedZone: tree.UnrestrictedName($2)}
+  }
+| DATABASE database_name
+  {
+    $$.val = tree.ZoneSpecifier{Database: tree.Name($2)}
+  }
+| TABLE table_name opt_partition
+  {
+    name := $2.unresolvedObjectName().ToTableName()
+    $$.val = tree.ZoneSpecifier{
       TableOrIndex: tree.TableIndexName{Table: name},
-        Partition: tree.Name($6),
-    }}
+    }
   }
-| SHOW ZONE CONFIGURATION FOR INDEX table_index_name
+| PARTITION partition_name OF TABLE table_name
   {
-    $$.val = &tree.ShowZoneConfig{ZoneSpecifier: tree.ZoneSpecifier{
-      TableOrIndex: $6.tableIndexName(),
-    }}
+    name := $5.unresolvedObjectName().ToTableName()
+    $$.val = tree.ZoneSpecifier{
+      TableOrIndex: tree.TableIndexName{Table: name},
+      Partition: tree.Name($2),
+    }
   }
-| SHOW ZONE CONFIGURATIONS
+| INDEX table_index_name
   {
-    $$.val = &tree.ShowZoneConfig{}
+    $$.val = tree.ZoneSpecifier{
+      TableOrIndex: $2.tableIndexName(),
+    }
   }
-| SHOW ALL ZONE CONFIGURATIONS
+
+// zone_range_key is a key given to SHOW ZONE CONFIGURATION FOR RANGE ... TO
+// .... A string is a pretty-printed key, like '/Table/53/1', and a byte string
+// is a raw key.
+zone_range_key:
+  SCONST
   {
-    $$.val = &tree.ShowZoneConfig{}
+    $$.val = tree.NewStrVal($1)
+  }
+| BCONST
+  {
+    $$.val = tree.NewBytesStrVal($1)
   }
 
 // %Help: SHOW RANGES - list ranges
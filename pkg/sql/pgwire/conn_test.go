@@ -922,3 +922,51 @@ func TestConnResultsBufferSize(t *testing.T) {
 	require.Equal(t, 0, a)
 	require.False(t, b)
 }
+
+func TestConnLocality(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.Background())
+
+	// Without the connection parameter, the session has no locality of its own.
+	{
+		var locality string
+		require.NoError(t, db.QueryRow(`SHOW crdb_locality`).Scan(&locality))
+		require.Equal(t, ``, locality)
+	}
+
+	pgURL, cleanup := sqlutils.PGUrl(t, s.ServingAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanup()
+	q := pgURL.Query()
+
+	q.Add(`crdb_locality`, `region`)
+	pgURL.RawQuery = q.Encode()
+	{
+		errDB, err := gosql.Open("postgres", pgURL.String())
+		require.NoError(t, err)
+		defer errDB.Close()
+		_, err = errDB.Exec(`SELECT 1`)
+		require.EqualError(t, err,
+			`pq: error parsing crdb_locality option value 'region': tier must be in the form "key=value" not "region"`)
+	}
+
+	q.Del(`crdb_locality`)
+	q.Add(`crdb_locality`, `region=east,dc=1`)
+	pgURL.RawQuery = q.Encode()
+
+	localityDB, err := gosql.Open("postgres", pgURL.String())
+	require.NoError(t, err)
+	defer localityDB.Close()
+
+	var locality string
+	require.NoError(t, localityDB.QueryRow(`SHOW crdb_locality`).Scan(&locality))
+	require.Equal(t, `region=east,dc=1`, locality)
+
+	// The locality is only used for planning. Statement statistics still record
+	// the region of the node that the session is connected to, which has none.
+	var regions int
+	require.NoError(t, localityDB.QueryRow(
+		`SELECT count(*) FROM crdb_internal.node_statement_statistics WHERE gateway_region IS NOT NULL`,
+	).Scan(&regions))
+	require.Equal(t, 0, regions)
+}
@@ -524,6 +524,16 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn) error {
 // -1 for the sentinel in case someone wants to set it to 0.
 const connResultsBufferSizeUnsetSentinel = -1
 
+// parseOptions parses the connection parameters of a startup message. Besides
+// "user" and the session variables that can be set, the following
+// CockroachDB-specific parameters are accepted:
+//
+//  - results_buffer_size overrides sql.defaults.results_buffer.size for the
+//    connection (see connResultsBufferSize).
+//  - crdb_locality is a locality like "region=us,dc=east" that is used in place
+//    of the gateway node's locality when planning the session's queries, so
+//    that the optimizer prefers indexes whose zones match it. It can be
+//    inspected with SHOW crdb_locality.
 func parseOptions(ctx context.Context, data []byte) (sql.SessionArgs, error) {
 	args := sql.SessionArgs{
 		SessionDefaults:       make(map[string]string),
@@ -557,6 +567,11 @@ func parseOptions(ctx context.Context, data []byte) (sql.SessionArgs, error) {
 				return sql.SessionArgs{}, pgerror.NewErrorf(pgerror.CodeProtocolViolationError,
 					"results_buffer_size option value '%s' cannot be negative", value)
 			}
+		case "crdb_locality":
+			if err := args.Locality.Set(value); err != nil {
+				return sql.SessionArgs{}, pgerror.NewErrorf(pgerror.CodeProtocolViolationError,
+					"error parsing crdb_locality option value '%s': %s", value, err)
+			}
 		default:
 			exists, configurable := sql.IsSessionVariableConfigurable(key)
 			if exists && configurable {
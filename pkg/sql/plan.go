@@ -292,6 +292,11 @@ type planTop struct {
 	// current statement is causing an auditing event. See exec_log.go.
 	auditEvents []auditEvent
 
+	// historicalTables lists the tables scanned by an AS OF SYSTEM TIME
+	// statement. Their zones are consulted if the read fails because its
+	// timestamp is before a GC threshold. See retryWithZoneGCTTL.
+	historicalTables []sqlbase.ID
+
 	// flags is populated during planning and execution.
 	flags planFlags
 
@@ -669,6 +674,8 @@ func (p *planner) newPlan(
 		return p.DropUser(ctx, n)
 	case *tree.Explain:
 		return p.Explain(ctx, n)
+	case *tree.ExplainZoneConfig:
+		return p.ExplainZoneConfig(ctx, n)
 	case *tree.Grant:
 		return p.Grant(ctx, n)
 	case *tree.Insert:
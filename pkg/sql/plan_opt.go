@@ -18,8 +18,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/execbuilder"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
@@ -227,6 +229,20 @@ func (opc *optPlanningCtx) reset() {
 	p := opc.p
 	opc.catalog.reset()
 	opc.optimizer.Init(p.EvalContext())
+	// Queries are planned for the locality given by the client with the
+	// crdb_locality connection parameter, if any, rather than for the gateway
+	// node's. Other uses of the locality are not affected.
+	gatewayLocality := p.EvalContext().Locality
+	if len(p.SessionData().Locality.Tiers) != 0 {
+		gatewayLocality = p.SessionData().Locality
+	}
+	opc.optimizer.SetZoneConstraintEvalContext(cat.ZoneConstraintEvalContext{
+		GatewayLocality:    gatewayLocality,
+		ClusterDefaultZone: config.DefaultZoneConfigRef(),
+		// Share the catalog's resolver, so that zones resolved for the
+		// catalog's indexes are not resolved again.
+		ZoneConfigStore: &CachedZoneConfigStore{cfg: opc.catalog.cfg, zones: opc.catalog.zones},
+	})
 	opc.flags = planFlagOptUsed
 
 	// We only allow memo caching for SELECT/INSERT/UPDATE/DELETE. We could
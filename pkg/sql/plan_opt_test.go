@@ -18,10 +18,12 @@ import (
 	"context"
 	gosql "database/sql"
 	"fmt"
+	"net/url"
 	"reflect"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
@@ -319,6 +321,52 @@ func TestQueryCache(t *testing.T) {
 		h.AssertStats(t, 1 /* hits */, 2 /* misses */)
 	})
 
+	// Test that sessions with different localities, which can change the best
+	// plan, do not share memos.
+	t.Run("locality", func(t *testing.T) {
+		h := makeQueryCacheTestHelper(t, 1 /* numConns */)
+		defer h.Stop()
+		pgURL, cleanup := sqlutils.PGUrl(t, h.srv.ServingAddr(), t.Name(), url.User(security.RootUser))
+		defer cleanup()
+		pgURL.Path = "db1"
+		openSession := func(locality string) *gosql.DB {
+			u := pgURL
+			q := u.Query()
+			q.Add("crdb_locality", locality)
+			u.RawQuery = q.Encode()
+			db, err := gosql.Open("postgres", u.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Use a single connection, so that EXECUTE finds the statements that
+			// were prepared on it.
+			db.SetMaxOpenConns(1)
+			return db
+		}
+		eastDB, westDB := openSession("region=east"), openSession("region=west")
+		defer eastDB.Close()
+		defer westDB.Close()
+		east, west := sqlutils.MakeSQLRunner(eastDB), sqlutils.MakeSQLRunner(westDB)
+
+		east.CheckQueryResults(t, "SELECT * FROM t", [][]string{{"1", "1"}})
+		h.AssertStats(t, 0 /* hits */, 1 /* misses */)
+		east.CheckQueryResults(t, "SELECT * FROM t", [][]string{{"1", "1"}})
+		h.AssertStats(t, 1 /* hits */, 1 /* misses */)
+		west.CheckQueryResults(t, "SELECT * FROM t", [][]string{{"1", "1"}})
+		h.AssertStats(t, 1 /* hits */, 2 /* misses */)
+		west.CheckQueryResults(t, "SELECT * FROM t", [][]string{{"1", "1"}})
+		h.AssertStats(t, 2 /* hits */, 2 /* misses */)
+
+		// The memo of a statement prepared in one session is not used to prepare
+		// it in the other.
+		east.Exec(t, "PREPARE a AS SELECT * FROM t")
+		h.AssertStats(t, 2 /* hits */, 3 /* misses */)
+		west.Exec(t, "PREPARE a AS SELECT * FROM t")
+		h.AssertStats(t, 2 /* hits */, 4 /* misses */)
+		east.CheckQueryResults(t, "EXECUTE a", [][]string{{"1", "1"}})
+		west.CheckQueryResults(t, "EXECUTE a", [][]string{{"1", "1"}})
+	})
+
 	// Test that a schema change triggers cache invalidation.
 	t.Run("schemachange-prepare", func(t *testing.T) {
 		h := makeQueryCacheTestHelper(t, 2 /* numConns */)
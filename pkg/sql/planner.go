@@ -145,6 +145,10 @@ type planner struct {
 	// query.
 	cancelChecker *sqlbase.CancelChecker
 
+	// readLocality, if set, counts the constrained index reads planned by the
+	// session, and how many of them were local to the gateway.
+	readLocality *sessionReadLocality
+
 	// isPreparing is true if this planner is currently preparing.
 	isPreparing bool
 
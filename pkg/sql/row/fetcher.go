@@ -192,6 +192,10 @@ type Fetcher struct {
 	// table has no interleave children.
 	mustDecodeIndexKey bool
 
+	// partitionBoundaries, if set, are the sorted keys at which the zones of
+	// the scanned index change. See SetPartitionBoundaries.
+	partitionBoundaries []roachpb.Key
+
 	// returnRangeInfo, if set, causes the underlying kvBatchFetcher to return
 	// information about the ranges descriptors/leases uses in servicing the
 	// requests. This has some cost, so it's only enabled by DistSQL when this
@@ -240,6 +244,16 @@ func (rf *Fetcher) Reset() {
 	}
 }
 
+// SetPartitionBoundaries sets the sorted keys at which the zones of the scanned
+// index change, such as the boundaries of its partitions. Forward scans with
+// limited batches then do not let a batch cross one of these keys, and the
+// batches after it start again from the first batch size. This avoids reading
+// more than needed from remote partitions when the limit is satisfied in the
+// local one. The boundaries apply to the scans started after this call.
+func (rf *Fetcher) SetPartitionBoundaries(boundaries []roachpb.Key) {
+	rf.partitionBoundaries = boundaries
+}
+
 // Init sets up a Fetcher for a given table and index. If we are using a
 // non-primary index, tables.ValNeededForCol can only refer to columns in the
 // index.
@@ -468,6 +482,7 @@ func (rf *Fetcher) StartScan(
 	if err != nil {
 		return err
 	}
+	f.partitionBoundaries = rf.partitionBoundaries
 	return rf.StartScanFrom(ctx, &f)
 }
 
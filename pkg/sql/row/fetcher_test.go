@@ -337,6 +337,148 @@ func TestNextRowBatchLimiting(t *testing.T) {
 	}
 }
 
+// partitionBoundaries returns the keys of the primary index of the table at
+// the given primary key values.
+func partitionBoundaries(tableDesc *sqlbase.ImmutableTableDescriptor, pks ...int64) []roachpb.Key {
+	prefix := tableDesc.IndexSpan(tableDesc.PrimaryIndex.ID).Key
+	boundaries := make([]roachpb.Key, len(pks))
+	for i, pk := range pks {
+		boundaries[i] = encoding.EncodeVarintAscending(append(roachpb.Key(nil), prefix...), pk)
+	}
+	return boundaries
+}
+
+// TestNextRowPartitionBoundaries checks that scans with limited batches read
+// every row when batches are split at partition boundaries.
+func TestNextRowPartitionBoundaries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	const nRows = 3000
+	sqlutils.CreateTable(
+		t, sqlDB, "t",
+		"k INT PRIMARY KEY, v INT",
+		nRows,
+		sqlutils.ToRowFn(sqlutils.RowIdxFn, sqlutils.RowModuloFn(42)),
+	)
+	tableDesc := sqlbase.GetImmutableTableDescriptor(kvDB, sqlutils.TestDB, "t")
+
+	var valNeededForCol util.FastIntSet
+	valNeededForCol.AddRange(0, 1)
+	args := []initFetcherArgs{
+		{
+			tableDesc:       tableDesc,
+			indexIdx:        0,
+			valNeededForCol: valNeededForCol,
+		},
+	}
+
+	for _, limitHint := range []int64{1, 10, 1000} {
+		t.Run(fmt.Sprintf("limitHint=%d", limitHint), func(t *testing.T) {
+			rf, err := initFetcher(args, false /*reverseScan*/, &sqlbase.DatumAlloc{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rf.SetPartitionBoundaries(partitionBoundaries(tableDesc, 5, 1000, 2000))
+
+			if err := rf.StartScan(
+				ctx,
+				client.NewTxn(ctx, kvDB, 0, client.RootTxn),
+				roachpb.Spans{tableDesc.IndexSpan(tableDesc.PrimaryIndex.ID)},
+				true, /*limitBatches*/
+				limitHint,
+				false, /*traceKV*/
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			count := 0
+			for {
+				datums, _, _, err := rf.NextRowDecoded(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if datums == nil {
+					break
+				}
+				count++
+				if k := int64(*datums[0].(*tree.DInt)); k != int64(count) {
+					t.Fatalf("expected row %d, got %d", count, k)
+				}
+			}
+			if count != nRows {
+				t.Fatalf("expected %d rows, got %d rows", nRows, count)
+			}
+		})
+	}
+}
+
+// BenchmarkNextRowPartitionBoundaries reads the rows of the first of three
+// partitions of a table, as a query with a limit whose rows are all in its
+// local partition does, with and without partition boundaries. Without them,
+// the growing batch sizes prefetch the other partitions.
+func BenchmarkNextRowPartitionBoundaries(b *testing.B) {
+	ctx := context.Background()
+
+	s, sqlDB, kvDB := serverutils.StartServer(b, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	const nRows, partitionRows = 30000, 10000
+	sqlutils.CreateTable(
+		b, sqlDB, "t",
+		"k INT PRIMARY KEY, v INT",
+		nRows,
+		sqlutils.ToRowFn(sqlutils.RowIdxFn, sqlutils.RowModuloFn(42)),
+	)
+	tableDesc := sqlbase.GetImmutableTableDescriptor(kvDB, sqlutils.TestDB, "t")
+
+	var valNeededForCol util.FastIntSet
+	valNeededForCol.AddRange(0, 1)
+	args := []initFetcherArgs{
+		{
+			tableDesc:       tableDesc,
+			indexIdx:        0,
+			valNeededForCol: valNeededForCol,
+		},
+	}
+
+	for _, boundaries := range []bool{false, true} {
+		b.Run(fmt.Sprintf("boundaries=%t", boundaries), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rf, err := initFetcher(args, false /*reverseScan*/, &sqlbase.DatumAlloc{})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if boundaries {
+					rf.SetPartitionBoundaries(
+						partitionBoundaries(tableDesc, partitionRows+1, 2*partitionRows+1),
+					)
+				}
+				if err := rf.StartScan(
+					ctx,
+					client.NewTxn(ctx, kvDB, 0, client.RootTxn),
+					roachpb.Spans{tableDesc.IndexSpan(tableDesc.PrimaryIndex.ID)},
+					true,  /*limitBatches*/
+					10,    /*limitHint*/
+					false, /*traceKV*/
+				); err != nil {
+					b.Fatal(err)
+				}
+				for j := 0; j < partitionRows; j++ {
+					if datums, _, _, err := rf.NextRowDecoded(ctx); err != nil {
+						b.Fatal(err)
+					} else if datums == nil {
+						b.Fatalf("expected %d rows, got %d", partitionRows, j)
+					}
+				}
+			}
+		})
+	}
+}
+
 // Regression test for #29374. Ensure that RowFetcher can handle multi-span
 // fetches where individual batches end in the middle of a multi-column family
 // row with not-null columns.
@@ -17,6 +17,7 @@ package row
 import (
 	"bytes"
 	"context"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -53,6 +54,10 @@ type txnKVFetcher struct {
 	// returnRangeInfo, if set, causes the kvBatchFetcher to populate rangeInfos.
 	// See also rowFetcher.returnRangeInfo.
 	returnRangeInfo bool
+	// partitionBoundaries, if set, are sorted keys that batches of forward scans
+	// with a batch limit do not cross. The batch size starts again from
+	// firstBatchLimit after each boundary. See Fetcher.SetPartitionBoundaries.
+	partitionBoundaries []roachpb.Key
 
 	fetchEnd bool
 	batchIdx int
@@ -197,8 +202,50 @@ func makeKVBatchFetcher(
 	}, nil
 }
 
+// splitSpansAtNextBoundary splits the ordered spans at the first of the sorted
+// boundaries that is after the start of the first span. It returns the parts
+// of the spans before that boundary and the parts after it, which are nil if
+// there is no such boundary.
+func splitSpansAtNextBoundary(
+	spans roachpb.Spans, boundaries []roachpb.Key,
+) (before, after roachpb.Spans) {
+	if len(spans) == 0 {
+		return spans, nil
+	}
+	start := spans[0].Key
+	i := sort.Search(len(boundaries), func(i int) bool {
+		return boundaries[i].Compare(start) > 0
+	})
+	if i == len(boundaries) {
+		return spans, nil
+	}
+	boundary := boundaries[i]
+
+	for j := range spans {
+		span := spans[j]
+		switch {
+		case span.Key.Compare(boundary) >= 0:
+			after = append(after, span)
+		case span.EndKey == nil || span.EndKey.Compare(boundary) <= 0:
+			before = append(before, span)
+		default:
+			before = append(before, roachpb.Span{Key: span.Key, EndKey: boundary})
+			after = append(after, roachpb.Span{Key: boundary, EndKey: span.EndKey})
+		}
+	}
+	return before, after
+}
+
 // fetch retrieves spans from the kv
 func (f *txnKVFetcher) fetch(ctx context.Context) error {
+	// If the spans cross a partition boundary, only request the parts before
+	// it. The parts after it are requested by the next fetches, whose batch
+	// sizes start again from the first batch limit.
+	var deferredSpans roachpb.Spans
+	if len(f.partitionBoundaries) != 0 && f.useBatchLimit && !f.reverse {
+		f.spans, deferredSpans = splitSpansAtNextBoundary(f.spans, f.partitionBoundaries)
+	}
+
 	var ba roachpb.BatchRequest
 	ba.Header.MaxSpanRequestKeys = f.getBatchSize()
 	ba.Header.ReturnRangeInfo = f.returnRangeInfo
@@ -279,6 +326,15 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 		}
 	}
 
+	if len(deferredSpans) != 0 {
+		f.fetchEnd = false
+		f.spans = append(f.spans, deferredSpans...)
+		if !sawResumeSpan {
+			// The next fetch starts after the partition boundary.
+			f.batchIdx = 0
+			return nil
+		}
+	}
 	f.batchIdx++
 
 	// TODO(radu): We should fetch the next chunk in the background instead of waiting for the next
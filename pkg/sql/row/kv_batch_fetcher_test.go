@@ -0,0 +1,78 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package row
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestSplitSpansAtNextBoundary(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	span := func(key, endKey string) roachpb.Span {
+		s := roachpb.Span{Key: roachpb.Key(key)}
+		if endKey != "" {
+			s.EndKey = roachpb.Key(endKey)
+		}
+		return s
+	}
+	boundaries := []roachpb.Key{roachpb.Key("c"), roachpb.Key("f")}
+
+	testCases := []struct {
+		spans         roachpb.Spans
+		before, after roachpb.Spans
+	}{
+		// No boundary after the first span's start.
+		{
+			spans:  roachpb.Spans{span("f", "h"), span("i", "")},
+			before: roachpb.Spans{span("f", "h"), span("i", "")},
+		},
+		// A span that crosses the boundary is split.
+		{
+			spans:  roachpb.Spans{span("a", "d")},
+			before: roachpb.Spans{span("a", "c")},
+			after:  roachpb.Spans{span("c", "d")},
+		},
+		// Spans on either side of the boundary, including one ending at it.
+		{
+			spans:  roachpb.Spans{span("a", "b"), span("b", "c"), span("d", "e"), span("g", "")},
+			before: roachpb.Spans{span("a", "b"), span("b", "c")},
+			after:  roachpb.Spans{span("d", "e"), span("g", "")},
+		},
+		// Only the next boundary is used.
+		{
+			spans:  roachpb.Spans{span("d", "z")},
+			before: roachpb.Spans{span("d", "f")},
+			after:  roachpb.Spans{span("f", "z")},
+		},
+		// Point spans.
+		{
+			spans:  roachpb.Spans{span("a", ""), span("c", "")},
+			before: roachpb.Spans{span("a", "")},
+			after:  roachpb.Spans{span("c", "")},
+		},
+	}
+
+	for i, tc := range testCases {
+		before, after := splitSpansAtNextBoundary(tc.spans, boundaries)
+		if !reflect.DeepEqual(before, tc.before) || !reflect.DeepEqual(after, tc.after) {
+			t.Errorf("%d: expected %s and %s, got %s and %s", i, tc.before, tc.after, before, after)
+		}
+	}
+}
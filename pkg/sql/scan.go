@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
@@ -123,6 +124,11 @@ type scanNode struct {
 
 	// Indicates if this scan is the source for a delete node.
 	isDeleteSource bool
+
+	// zone is the zone which constrains placement of the index's range
+	// replicas. It is only set when the scanNode is created via the exec
+	// factory.
+	zone cat.Zone
 }
 
 // scanVisibility represents which table columns should be included in a scan.
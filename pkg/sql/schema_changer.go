@@ -62,6 +62,15 @@ var schemaChangeLeaseRenewFraction = settings.RegisterFloatSetting(
 	0.5,
 )
 
+// zoneConstraintAwareSchemaChange controls whether the backfills of tables
+// with partition-level zone configs check the leaseholders of each partition
+// against the partition's constraints. See getBackfillZoneSpans.
+var zoneConstraintAwareSchemaChange = settings.RegisterBoolSetting(
+	"schemachanger.backfill.zone_constraint_aware.enabled",
+	"report the ranges of each partition of a table whose leaseholder violates the partition's zone constraints in the backfill trace",
+	false,
+)
+
 // This is a delay [0.9 * asyncSchemaChangeDelay, 1.1 * asyncSchemaChangeDelay)
 // added to an attempt to run a schema change via the asynchronous path.
 // This delay allows the synchronous path to execute the schema change
@@ -36,12 +36,15 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
@@ -2991,6 +2994,45 @@ may increase either contention or retry errors, or both.`,
 			Info: "This function is used only by CockroachDB's developers for testing purposes.",
 		},
 	),
+
+	"crdb_internal.fk_zone_locality_mismatch": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categorySystemInfo,
+			DistsqlBlacklist: true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"table_id", types.Int}, {"constraint_name", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				tableID := sqlbase.ID(tree.MustBeDInt(args[0]))
+				name := string(tree.MustBeDString(args[1]))
+				return fkZoneLocalityMismatch(ctx, tableID, name)
+			},
+			Info: "Returns whether the indexes on the two sides of the named foreign key " +
+				"constraint of the table are in zones that place replicas or leases differently. " +
+				"Returns NULL if the table has no such foreign key.",
+		},
+	),
+
+	"crdb_internal.fk_co_location_score": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categorySystemInfo,
+			DistsqlBlacklist: true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"table_id", types.Int}, {"constraint_name", types.String}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				tableID := sqlbase.ID(tree.MustBeDInt(args[0]))
+				name := string(tree.MustBeDString(args[1]))
+				return fkCoLocationScore(ctx, tableID, name)
+			},
+			Info: "Returns the Jaccard similarity, from 0 to 1, of the replica constraints of the " +
+				"zones of the indexes on the two sides of the named foreign key constraint of the " +
+				"table. A score below 0.5 suggests aligning the zone configs of the two tables. " +
+				"Returns NULL if the table has no such foreign key.",
+		},
+	),
 }
 
 var lengthImpls = makeBuiltin(tree.FunctionProperties{Category: categoryString},
@@ -4538,3 +4580,97 @@ func recentTimestamp(ctx *tree.EvalContext) (time.Time, error) {
 	}
 	return ctx.StmtTimestamp.Add(offset), nil
 }
+
+// fkZoneLocalityMismatch implements crdb_internal.fk_zone_locality_mismatch.
+func fkZoneLocalityMismatch(
+	ctx *tree.EvalContext, tableID sqlbase.ID, name string,
+) (tree.Datum, error) {
+	referencing, referenced, err := fkIndexZones(ctx, tableID, name)
+	if err != nil || referencing == nil {
+		return tree.DNull, err
+	}
+	return tree.MakeDBool(tree.DBool(cat.ZoneLocalityMismatch(referencing, referenced))), nil
+}
+
+// fkCoLocationScore implements crdb_internal.fk_co_location_score.
+func fkCoLocationScore(
+	ctx *tree.EvalContext, tableID sqlbase.ID, name string,
+) (tree.Datum, error) {
+	referencing, referenced, err := fkIndexZones(ctx, tableID, name)
+	if err != nil || referencing == nil {
+		return tree.DNull, err
+	}
+	return tree.NewDFloat(tree.DFloat(cat.ZoneConstraintOverlap(referencing, referenced))), nil
+}
+
+// fkIndexZones returns the zones of the indexes on the two sides of the named
+// foreign key constraint of the table, or nil zones if the table has no such
+// foreign key.
+func fkIndexZones(
+	ctx *tree.EvalContext, tableID sqlbase.ID, name string,
+) (referencing, referenced *config.ZoneConfig, _ error) {
+	table, err := sqlbase.GetTableDescFromID(ctx.Ctx(), ctx.Txn, tableID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, idx := range table.AllNonDropIndexes() {
+		if !idx.ForeignKey.IsSet() || idx.ForeignKey.Name != name {
+			continue
+		}
+		referencedTable, err := sqlbase.GetTableDescFromID(ctx.Ctx(), ctx.Txn, idx.ForeignKey.Table)
+		if err != nil {
+			return nil, nil, err
+		}
+		referencing, err := indexZone(ctx, table, idx.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		referenced, err := indexZone(ctx, referencedTable, idx.ForeignKey.Index)
+		if err != nil {
+			return nil, nil, err
+		}
+		return referencing, referenced, nil
+	}
+	return nil, nil, nil
+}
+
+// indexZone returns the zone that applies to the index of the table. Fields
+// that are not set on the index's subzone are inherited from the table, then
+// from the database, and finally from the default zone.
+func indexZone(
+	ctx *tree.EvalContext, table *sqlbase.TableDescriptor, indexID sqlbase.IndexID,
+) (*config.ZoneConfig, error) {
+	var zone *config.ZoneConfig
+	inherit := func(parent *config.ZoneConfig) {
+		if zone == nil {
+			z := *parent
+			zone = &z
+		} else {
+			zone.InheritFromParent(*parent)
+		}
+	}
+	for _, id := range []uint32{uint32(table.ID), uint32(table.ParentID), keys.RootNamespaceID} {
+		kv, err := ctx.Txn.Get(ctx.Ctx(), config.MakeZoneKey(id))
+		if err != nil {
+			return nil, err
+		}
+		if kv.Value == nil {
+			continue
+		}
+		var raw config.ZoneConfig
+		if err := kv.ValueProto(&raw); err != nil {
+			return nil, err
+		}
+		if id == uint32(table.ID) {
+			if subzone := raw.GetSubzone(uint32(indexID), ""); subzone != nil {
+				inherit(&subzone.Config)
+			}
+			if raw.IsSubzonePlaceholder() {
+				continue
+			}
+		}
+		inherit(&raw)
+	}
+	inherit(config.DefaultZoneConfigRef())
+	return zone, nil
+}
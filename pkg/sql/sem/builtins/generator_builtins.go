@@ -16,15 +16,20 @@ package builtins
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/util/arith"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 )
 
 // See the comments at the start of generators.go for details about
@@ -142,6 +147,18 @@ var generators = map[string]builtinDefinition{
 		),
 	),
 
+	"crdb_internal.zone_constraint_histogram": makeBuiltin(
+		genProps(zoneConstraintHistogramGeneratorType.Labels),
+		makeGeneratorOverload(
+			tree.ArgTypes{{"key", types.String}},
+			zoneConstraintHistogramGeneratorType,
+			makeZoneConstraintHistogramGenerator,
+			"Returns, for each value of the given locality key, the number of tables "+
+				"whose effective zone requires replicas to be placed in that value.\n\n"+
+				"Tables in the system database are not counted.",
+		),
+	),
+
 	"generate_subscripts": makeBuiltin(genProps(subscriptsValueGeneratorLabels),
 		// See https://www.postgresql.org/docs/current/static/functions-srf.html#FUNCTIONS-SRF-SUBSCRIPTS
 		makeGeneratorOverload(
@@ -589,6 +606,121 @@ var noDatums tree.Datums
 // Values implements the tree.ValueGenerator interface.
 func (s *unaryValueGenerator) Values() tree.Datums { return noDatums }
 
+// zoneConstraintHistogramGenerator supports the execution of
+// crdb_internal.zone_constraint_histogram(key).
+type zoneConstraintHistogramGenerator struct {
+	evalCtx *tree.EvalContext
+	key     string
+
+	values []string
+	counts map[string]int
+	idx    int
+}
+
+var zoneConstraintHistogramGeneratorType = types.TTuple{
+	Types:  []types.T{types.String, types.Int},
+	Labels: []string{"value", "tables"},
+}
+
+func makeZoneConstraintHistogramGenerator(
+	ctx *tree.EvalContext, args tree.Datums,
+) (tree.ValueGenerator, error) {
+	return &zoneConstraintHistogramGenerator{
+		evalCtx: ctx,
+		key:     string(tree.MustBeDString(args[0])),
+	}, nil
+}
+
+// ResolvedType implements the tree.ValueGenerator interface.
+func (*zoneConstraintHistogramGenerator) ResolvedType() types.T {
+	return zoneConstraintHistogramGeneratorType
+}
+
+// Start implements the tree.ValueGenerator interface. It reads the zones and
+// tables and counts, for each value of the key, the tables whose effective
+// zone has a required constraint on that key and value in any of its replica
+// constraint sets. A table's effective constraints are those of its own zone,
+// else those of its database's zone, else those of the default zone.
+func (g *zoneConstraintHistogramGenerator) Start() error {
+	ctx := g.evalCtx.Ctx()
+	zoneRows, err := g.evalCtx.InternalExecutor.Query(
+		ctx, "zone-constraint-histogram-zones", g.evalCtx.Txn,
+		`SELECT id, config FROM system.zones`)
+	if err != nil {
+		return err
+	}
+	zones := make(map[uint32]*config.ZoneConfig, len(zoneRows))
+	for _, r := range zoneRows {
+		var zone config.ZoneConfig
+		if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(r[1])), &zone); err != nil {
+			return err
+		}
+		zones[uint32(tree.MustBeDInt(r[0]))] = &zone
+	}
+
+	tableRows, err := g.evalCtx.InternalExecutor.Query(
+		ctx, "zone-constraint-histogram-tables", g.evalCtx.Txn,
+		`SELECT id, "parentID" FROM system.namespace WHERE "parentID" NOT IN (0, $1)`,
+		keys.SystemDatabaseID)
+	if err != nil {
+		return err
+	}
+
+	g.counts = make(map[string]int)
+	for _, r := range tableRows {
+		tableID, dbID := uint32(tree.MustBeDInt(r[0])), uint32(tree.MustBeDInt(r[1]))
+		var zone cat.Zone
+		for _, id := range []uint32{tableID, dbID, keys.RootNamespaceID} {
+			if z, ok := zones[id]; ok && !z.IsSubzonePlaceholder() && !z.InheritedConstraints {
+				zone = z
+				break
+			}
+		}
+		if zone == nil {
+			continue
+		}
+
+		// Count each value at most once per table, even if it appears in several
+		// replica constraint sets.
+		seen := make(map[string]struct{})
+		for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+			set := zone.ReplicaConstraints(i)
+			for j, m := 0, set.ConstraintCount(); j < m; j++ {
+				c := set.Constraint(j)
+				if c.IsRequired() && c.GetKey() == g.key {
+					seen[c.GetValue()] = struct{}{}
+				}
+			}
+		}
+		for value := range seen {
+			g.counts[value]++
+		}
+	}
+
+	g.values = g.values[:0]
+	for value := range g.counts {
+		g.values = append(g.values, value)
+	}
+	sort.Strings(g.values)
+	g.idx = -1
+	return nil
+}
+
+// Close implements the tree.ValueGenerator interface.
+func (g *zoneConstraintHistogramGenerator) Close() {}
+
+// Next implements the tree.ValueGenerator interface.
+func (g *zoneConstraintHistogramGenerator) Next() (bool, error) {
+	g.idx++
+	return g.idx < len(g.values), nil
+}
+
+// Values implements the tree.ValueGenerator interface.
+func (g *zoneConstraintHistogramGenerator) Values() tree.Datums {
+	value := g.values[g.idx]
+	return tree.Datums{tree.NewDString(value), tree.NewDInt(tree.DInt(g.counts[value]))}
+}
+
 func jsonAsText(j json.JSON) (tree.Datum, error) {
 	text, err := j.AsText()
 	if err != nil {
@@ -451,6 +451,12 @@ func (*Explain) StatementType() StatementType { return Rows }
 // StatementTag returns a short string identifying the type of statement.
 func (*Explain) StatementTag() string { return "EXPLAIN" }
 
+// StatementType implements the Statement interface.
+func (*ExplainZoneConfig) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ExplainZoneConfig) StatementTag() string { return "EXPLAIN ZONE CONFIGURATION" }
+
 // StatementType implements the Statement interface.
 func (*Export) StatementType() StatementType { return Rows }
 
@@ -897,6 +903,7 @@ func (n *DropSequence) String() string              { return AsString(n) }
 func (n *DropUser) String() string                  { return AsString(n) }
 func (n *Execute) String() string                   { return AsString(n) }
 func (n *Explain) String() string                   { return AsString(n) }
+func (n *ExplainZoneConfig) String() string         { return AsString(n) }
 func (n *Export) String() string                    { return AsString(n) }
 func (n *Grant) String() string                     { return AsString(n) }
 func (n *GrantRole) String() string                 { return AsString(n) }
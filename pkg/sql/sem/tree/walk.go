@@ -1213,6 +1213,26 @@ func (stmt *SetZoneConfig) walkStmt(v Visitor) Statement {
 			ret.Options = newOpts
 		}
 	}
+	if stmt.Template != nil {
+		e, changed := WalkExpr(v, stmt.Template)
+		if changed {
+			if ret == stmt {
+				newStmt := *stmt
+				ret = &newStmt
+			}
+			ret.Template = e
+		}
+	}
+	if stmt.TemplateRegions != nil {
+		exprs, changed := walkExprSlice(v, stmt.TemplateRegions)
+		if changed {
+			if ret == stmt {
+				newStmt := *stmt
+				ret = &newStmt
+			}
+			ret.TemplateRegions = exprs
+		}
+	}
 	return ret
 }
 
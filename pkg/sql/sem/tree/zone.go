@@ -67,11 +67,20 @@ func (node *ZoneSpecifier) String() string { return AsString(node) }
 // statement.
 type ShowZoneConfig struct {
 	ZoneSpecifier
+
+	// StartKey and EndKey are set instead of the zone specifier if the
+	// statement shows the zone config for the key span [StartKey, EndKey).
+	StartKey, EndKey Expr
 }
 
 // Format implements the NodeFormatter interface.
 func (node *ShowZoneConfig) Format(ctx *FmtCtx) {
-	if node.ZoneSpecifier == (ZoneSpecifier{}) {
+	if node.StartKey != nil {
+		ctx.WriteString("SHOW ZONE CONFIGURATION FOR RANGE ")
+		ctx.FormatNode(node.StartKey)
+		ctx.WriteString(" TO ")
+		ctx.FormatNode(node.EndKey)
+	} else if node.ZoneSpecifier == (ZoneSpecifier{}) {
 		ctx.WriteString("SHOW ZONE CONFIGURATIONS")
 	} else {
 		ctx.WriteString("SHOW ZONE CONFIGURATION FOR ")
@@ -79,6 +88,25 @@ func (node *ShowZoneConfig) Format(ctx *FmtCtx) {
 	}
 }
 
+// ExplainZoneConfig represents an EXPLAIN ZONE CONFIGURATION statement.
+type ExplainZoneConfig struct {
+	ZoneSpecifier
+
+	// Verbose is set if the statement should also show each level of the zone
+	// hierarchy that the object inherits from.
+	Verbose bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExplainZoneConfig) Format(ctx *FmtCtx) {
+	ctx.WriteString("EXPLAIN ")
+	if node.Verbose {
+		ctx.WriteString("(VERBOSE) ")
+	}
+	ctx.WriteString("ZONE CONFIGURATION FOR ")
+	ctx.FormatNode(&node.ZoneSpecifier)
+}
+
 // SetZoneConfig represents an ALTER DATABASE/TABLE... CONFIGURE ZONE
 // statement.
 type SetZoneConfig struct {
@@ -86,6 +114,12 @@ type SetZoneConfig struct {
 	SetDefault bool
 	YAMLConfig Expr
 	Options    KVOptions
+
+	// Template is set if the statement is CONFIGURE ZONE USING TEMPLATE, to
+	// the name of the zone template. TemplateRegions are the regions that
+	// the template is applied to.
+	Template        Expr
+	TemplateRegions Exprs
 }
 
 // Format implements the NodeFormatter interface.
@@ -102,6 +136,14 @@ func (node *SetZoneConfig) Format(ctx *FmtCtx) {
 			ctx.WriteString("= ")
 			ctx.FormatNode(node.YAMLConfig)
 		}
+	} else if node.Template != nil {
+		ctx.WriteString("USING TEMPLATE ")
+		ctx.FormatNode(node.Template)
+		if node.TemplateRegions != nil {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.TemplateRegions)
+			ctx.WriteByte(')')
+		}
 	} else {
 		ctx.WriteString("USING ")
 		kvOptions := node.Options
@@ -20,6 +20,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
@@ -87,6 +88,11 @@ type SessionData struct {
 	// ResultsBufferSize specifies the size at which the pgwire results buffer
 	// will self-flush.
 	ResultsBufferSize int64
+	// Locality, if set, is used in place of the gateway node's locality when
+	// planning queries, so that indexes whose zones match it are preferred. It
+	// allows clients to get the plans of a particular region without connecting
+	// to a node in that region.
+	Locality roachpb.Locality
 	// AllowPrepareAsOptPlan must be set to allow use of
 	//   PREPARE name AS OPT PLAN '...'
 	AllowPrepareAsOptPlan bool
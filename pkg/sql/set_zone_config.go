@@ -25,6 +25,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
@@ -45,6 +47,11 @@ type setZoneConfigNode struct {
 	options       map[tree.Name]optionValue
 	setDefault    bool
 
+	// template and templateRegions are set if the statement is CONFIGURE ZONE
+	// USING TEMPLATE. templateRegions is nil if no regions were given.
+	template        func() (string, error)
+	templateRegions func() ([]string, error)
+
 	run setZoneConfigRun
 }
 
@@ -151,14 +158,71 @@ func (p *planner) SetZoneConfig(ctx context.Context, n *tree.SetZoneConfig) (pla
 		}
 	}
 
+	var template func() (string, error)
+	var templateRegions func() ([]string, error)
+	if n.Template != nil {
+		// We have a CONFIGURE ZONE USING TEMPLATE ... assignment.
+		var err error
+		template, err = p.TypeAsString(n.Template, "configure zone")
+		if err != nil {
+			return nil, err
+		}
+		if n.TemplateRegions != nil {
+			templateRegions, err = p.TypeAsStringArray(n.TemplateRegions, "configure zone")
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return &setZoneConfigNode{
-		zoneSpecifier: n.ZoneSpecifier,
-		yamlConfig:    yamlConfig,
-		options:       options,
-		setDefault:    n.SetDefault,
+		zoneSpecifier:   n.ZoneSpecifier,
+		yamlConfig:      yamlConfig,
+		options:         options,
+		setDefault:      n.SetDefault,
+		template:        template,
+		templateRegions: templateRegions,
 	}, nil
 }
 
+// setZoneTemplate replaces the replica count, replica constraints, and lease
+// preferences of the zone config with those of the template zone.
+func setZoneTemplate(c *config.ZoneConfig, zone cat.Zone) {
+	makeConstraints := func(set cat.ConstraintSet) []config.Constraint {
+		constraints := make([]config.Constraint, set.ConstraintCount())
+		for i := range constraints {
+			constraint := set.Constraint(i)
+			constraints[i] = config.Constraint{
+				Type:  config.Constraint_PROHIBITED,
+				Key:   constraint.GetKey(),
+				Value: constraint.GetValue(),
+			}
+			if constraint.IsRequired() {
+				constraints[i].Type = config.Constraint_REQUIRED
+			}
+		}
+		return constraints
+	}
+
+	c.NumReplicas = proto.Int32(zone.ReplicaCount())
+	c.Constraints = nil
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		replConstraint := zone.ReplicaConstraints(i)
+		c.Constraints = append(c.Constraints, config.Constraints{
+			NumReplicas: replConstraint.ReplicaCount(),
+			Constraints: makeConstraints(replConstraint),
+		})
+	}
+	c.InheritedConstraints = false
+	c.LeasePreferences = nil
+	for i, n := 0, zone.LeasePreferenceCount(); i < n; i++ {
+		c.LeasePreferences = append(c.LeasePreferences, config.LeasePreference{
+			Constraints: makeConstraints(zone.LeasePreference(i)),
+		})
+	}
+	c.InheritedLeasePreferences = false
+}
+
 // setZoneConfigRun contains the run-time state of setZoneConfigNode during local execution.
 type setZoneConfigRun struct {
 	numAffected int
@@ -234,6 +298,40 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 
 		}
 	}
+	if n.template != nil {
+		// Set from a zone template.
+		name, err := n.template()
+		if err != nil {
+			return err
+		}
+		var regions []string
+		if n.templateRegions != nil {
+			if regions, err = n.templateRegions(); err != nil {
+				return err
+			}
+		}
+		template, ok := cat.LookupZoneTemplate(name)
+		if !ok {
+			return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"unknown zone config template: %q", name)
+		}
+		zone, err := template.Zone(regions...)
+		if err != nil {
+			return pgerror.NewError(pgerror.CodeInvalidParameterValueError, err.Error())
+		}
+		setters = append(setters, func(c *config.ZoneConfig) { setZoneTemplate(c, zone) })
+		fmt.Fprintf(&optionStr, "TEMPLATE %s", lex.EscapeSQLString(name))
+		if regions != nil {
+			optionStr.WriteString(" (")
+			for i := range regions {
+				if i > 0 {
+					optionStr.WriteString(", ")
+				}
+				optionStr.WriteString(lex.EscapeSQLString(regions[i]))
+			}
+			optionStr.WriteString(")")
+		}
+	}
 
 	// If the specifier is for a table, partition or index, this will
 	// resolve the table descriptor. If the specifier is for a database
@@ -317,6 +415,18 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 		return err
 	}
 
+	// Remember the zone that applied to the target before the change so that
+	// the effect of the change can be recorded in the event log. This is not
+	// known if the statement resets the zone to its inherited default.
+	var beforeZone *config.ZoneConfig
+	if !n.setDefault {
+		beforeZone = protoutil.Clone(completeZone).(*config.ZoneConfig)
+		if completeSubzone != nil {
+			beforeZone = protoutil.Clone(&completeSubzone.Config).(*config.ZoneConfig)
+		}
+	}
+	var changes []cat.ZoneChange
+
 	// Copy the fields set by the INHERIT field command.
 	partialZone.CopyFromZone(*completeZone, copyFromParentList)
 
@@ -406,6 +516,10 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 		}
 
 		// Validate that the result makes sense.
+		if err := cat.ValidateZone(&newZone); err != nil {
+			return pgerror.NewErrorf(pgerror.CodeCheckViolationError,
+				"could not validate zone config: %v", err)
+		}
 		if err := validateZoneAttrsAndLocalities(
 			params.ctx,
 			params.extendedEvalCtx.StatusServer.Nodes,
@@ -414,6 +528,10 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 			return err
 		}
 
+		if beforeZone != nil {
+			changes = cat.ZoneDiff(beforeZone, &newZone)
+		}
+
 		// Are we operating on an index?
 		if index == nil {
 			// No: the final zone config is the one we just processed.
@@ -490,15 +608,11 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 
 	// Record that the change has occurred for auditing.
 	var eventLogType EventLogType
-	info := struct {
-		Target  string
-		Config  string `json:",omitempty"`
-		Options string `json:",omitempty"`
-		User    string
-	}{
+	info := zoneConfigEventInfo{
 		Target:  config.CLIZoneSpecifier(&n.zoneSpecifier),
 		Config:  strings.TrimSpace(yamlConfig),
 		Options: optionStr.String(),
+		Changes: changes,
 		User:    params.SessionData().User,
 	}
 	if deleteZone {
@@ -516,6 +630,16 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 	)
 }
 
+// zoneConfigEventInfo is the info of the events that are logged when a zone
+// config is set or removed.
+type zoneConfigEventInfo struct {
+	Target  string
+	Config  string           `json:",omitempty"`
+	Options string           `json:",omitempty"`
+	Changes []cat.ZoneChange `json:",omitempty"`
+	User    string
+}
+
 func (n *setZoneConfigNode) Next(runParams) (bool, error) { return false, nil }
 func (n *setZoneConfigNode) Values() tree.Datums          { return nil }
 func (*setZoneConfigNode) Close(context.Context)          {}
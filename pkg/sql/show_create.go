@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/pkg/errors"
@@ -148,12 +150,16 @@ func ShowCreateSequence(
 // unless it is equal to the given dbPrefix. This allows us to elide
 // the prefix when the given table references other tables in the
 // current database.
+//
+// If zone is not nil, then each index that has its own replica constraints in
+// the zone is preceded by a comment listing those constraints.
 func ShowCreateTable(
 	ctx context.Context,
 	tn *tree.Name,
 	dbPrefix string,
 	desc *sqlbase.TableDescriptor,
 	lCtx *internalLookupCtx,
+	zone *config.ZoneConfig,
 	ignoreFKs bool,
 ) (string, error) {
 	a := &sqlbase.DatumAlloc{}
@@ -176,7 +182,9 @@ func ShowCreateTable(
 		}
 	}
 	if primaryKeyIsOnVisibleColumn {
-		f.WriteString(",\n\tCONSTRAINT ")
+		f.WriteString(",\n\t")
+		showCreateIndexZone(&desc.PrimaryIndex, zone, &f.Buffer)
+		f.WriteString("CONSTRAINT ")
 		formatQuoteNames(&f.Buffer, desc.PrimaryIndex.Name)
 		f.WriteString(" ")
 		f.WriteString(desc.PrimaryKeyString())
@@ -195,6 +203,7 @@ func ShowCreateTable(
 		if idx.ID != desc.PrimaryIndex.ID {
 			// Showing the primary index is handled above.
 			f.WriteString(",\n\t")
+			showCreateIndexZone(idx, zone, &f.Buffer)
 			f.WriteString(idx.SQLString(&sqlbase.AnonymousTable))
 			// Showing the INTERLEAVE and PARTITION BY for the primary index are
 			// handled last.
@@ -261,6 +270,23 @@ func formatQuoteNames(buf *bytes.Buffer, names ...string) {
 	buf.WriteString(f.CloseAndGetString())
 }
 
+// showCreateIndexZone writes a line with a comment documenting the replica
+// constraints of the specified index, if the index has a subzone with its own
+// constraints. See cat.FormatZoneAsComment.
+func showCreateIndexZone(idx *sqlbase.IndexDescriptor, zone *config.ZoneConfig, buf *bytes.Buffer) {
+	if zone == nil {
+		return
+	}
+	subzone := zone.GetSubzone(uint32(idx.ID), "" /* partition */)
+	if subzone == nil {
+		return
+	}
+	if subzone.Config.ReplicaConstraintsCount() != 0 {
+		buf.WriteString(cat.FormatZoneAsComment(&subzone.Config))
+		buf.WriteString("\n\t")
+	}
+}
+
 // showCreateInterleave returns an INTERLEAVE IN PARENT clause for the specified
 // index, if applicable.
 //
@@ -21,11 +21,15 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/pkg/errors"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -57,6 +61,9 @@ const (
 )
 
 func (p *planner) ShowZoneConfig(ctx context.Context, n *tree.ShowZoneConfig) (planNode, error) {
+	if n.StartKey != nil {
+		return p.showZoneConfigForSpan(n), nil
+	}
 	if n.ZoneSpecifier == (tree.ZoneSpecifier{}) {
 		planRenderNode, err := p.delegateQuery(ctx, "SHOW ZONE CONFIGURATIONS",
 			`SELECT zone_id, cli_specifier AS zone_name, cli_specifier, config_sql
@@ -80,6 +87,110 @@ func (p *planner) ShowZoneConfig(ctx context.Context, n *tree.ShowZoneConfig) (p
 	}, nil
 }
 
+var showZoneConfigForSpanColumns = sqlbase.ResultColumns{
+	{Name: "start_key", Typ: types.String},
+	{Name: "end_key", Typ: types.String},
+	{Name: "config_sql", Typ: types.String},
+}
+
+// showZoneConfigForSpan returns a SHOW ZONE CONFIGURATION FOR RANGE ... TO ...
+// statement, which shows the deepest zone config that applies to the key span,
+// including index and partition subzones. The span must not cross a zone
+// boundary, including the boundaries of the spans of subzones.
+func (p *planner) showZoneConfigForSpan(n *tree.ShowZoneConfig) planNode {
+	return &delayedNode{
+		name:    n.String(),
+		columns: showZoneConfigForSpanColumns,
+
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			startKey, err := p.evalZoneRangeKey(ctx, n.StartKey)
+			if err != nil {
+				return nil, err
+			}
+			endKey, err := p.evalZoneRangeKey(ctx, n.EndKey)
+			if err != nil {
+				return nil, err
+			}
+			if bytes.Compare(startKey, endKey) >= 0 {
+				return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+					"start key %s must be less than end key %s", startKey, endKey)
+			}
+
+			cfg := p.execCfg.Gossip.GetSystemConfig()
+			if cfg == nil {
+				return nil, errors.New("system config not yet available")
+			}
+			if splitKey := cfg.ComputeSplitKey(startKey, endKey); splitKey != nil {
+				return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+					"span %s-%s crosses a zone boundary at %s", startKey, endKey, splitKey)
+			}
+			// Check the span against the spans of the object's subzones
+			// themselves, rather than relying on them being split points.
+			if objectID, _, ok := config.DecodeObjectID(startKey); ok {
+				boundaries, err := cfg.GetSubzoneBoundariesForObject(objectID)
+				if err != nil {
+					return nil, err
+				}
+				for _, boundary := range boundaries {
+					if key := roachpb.RKey(boundary); startKey.Less(key) && key.Less(endKey) {
+						return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+							"span %s-%s crosses a zone boundary at %s", startKey, endKey, key)
+					}
+				}
+			}
+			zone, err := cfg.GetZoneConfigForKey(startKey)
+			if err != nil {
+				return nil, err
+			}
+
+			f := tree.NewFmtCtx(tree.FmtParsable)
+			if err := formatZoneConfigUsing(f, zone); err != nil {
+				return nil, err
+			}
+			v := p.newContainerValuesNode(showZoneConfigForSpanColumns, 1)
+			row := tree.Datums{
+				tree.NewDString(startKey.String()),
+				tree.NewDString(endKey.String()),
+				tree.NewDString(f.CloseAndGetString()),
+			}
+			if _, err := v.rows.AddRow(ctx, row); err != nil {
+				v.Close(ctx)
+				return nil, err
+			}
+			return v, nil
+		},
+	}
+}
+
+// evalZoneRangeKey evaluates a key given to SHOW ZONE CONFIGURATION FOR RANGE.
+// A string is parsed as a pretty-printed key, and a byte string is used as a
+// raw key.
+func (p *planner) evalZoneRangeKey(ctx context.Context, expr tree.Expr) (roachpb.RKey, error) {
+	typedExpr, err := p.analyzeExpr(
+		ctx, expr, nil, tree.IndexedVarHelper{}, types.Any, false, "SHOW ZONE CONFIGURATION")
+	if err != nil {
+		return nil, err
+	}
+	d, err := typedExpr.Eval(p.EvalContext())
+	if err != nil {
+		return nil, err
+	}
+	switch t := d.(type) {
+	case *tree.DString:
+		key, err := keys.UglyPrint(string(*t))
+		if err != nil {
+			return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"invalid key %s: %v", t, err)
+		}
+		return roachpb.RKey(key), nil
+	case *tree.DBytes:
+		return roachpb.RKey(*t), nil
+	default:
+		return nil, pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+			"key must be a string or bytes, not %s", d.ResolvedType())
+	}
+}
+
 // showZoneConfigRun contains the run-time state of showZoneConfigNode
 // during local execution.
 type showZoneConfigRun struct {
@@ -88,24 +199,42 @@ type showZoneConfigRun struct {
 }
 
 func (n *showZoneConfigNode) startExec(params runParams) error {
-	tblDesc, err := params.p.resolveTableForZone(params.ctx, &n.zoneSpecifier)
+	zoneID, zone, zs, err := params.p.getZoneConfigForSpecifier(params.ctx, n.zoneSpecifier)
 	if err != nil {
 		return err
 	}
 
-	targetID, err := resolveZone(
-		params.ctx, params.p.txn, &n.zoneSpecifier)
+	// Ensure subzone configs don't infect the output of config_bytes.
+	zone.Subzones = nil
+	zone.SubzoneSpans = nil
+
+	n.run.values = make(tree.Datums, len(showZoneConfigNodeColumns))
+	return generateZoneConfigIntrospectionValues(
+		n.run.values, tree.NewDInt(tree.DInt(zoneID)), &zs, zone)
+}
+
+// getZoneConfigForSpecifier returns the zone config that applies to the object
+// named by the zone specifier, along with the ID of the zone it is stored in and
+// a specifier naming that zone.
+func (p *planner) getZoneConfigForSpecifier(
+	ctx context.Context, zoneSpecifier tree.ZoneSpecifier,
+) (uint32, *config.ZoneConfig, tree.ZoneSpecifier, error) {
+	tblDesc, err := p.resolveTableForZone(ctx, &zoneSpecifier)
+	if err != nil {
+		return 0, nil, tree.ZoneSpecifier{}, err
+	}
+
+	targetID, err := resolveZone(ctx, p.txn, &zoneSpecifier)
 	if err != nil {
-		return err
+		return 0, nil, tree.ZoneSpecifier{}, err
 	}
 
-	index, partition, err := resolveSubzone(
-		params.ctx, params.p.txn, &n.zoneSpecifier, targetID, tblDesc)
+	index, partition, err := resolveSubzone(ctx, p.txn, &zoneSpecifier, targetID, tblDesc)
 	if err != nil {
-		return err
+		return 0, nil, tree.ZoneSpecifier{}, err
 	}
 
-	zoneID, zone, subzone, err := GetZoneConfigInTxn(params.ctx, params.p.txn,
+	zoneID, zone, subzone, err := GetZoneConfigInTxn(ctx, p.txn,
 		uint32(targetID), index, partition, false /* getInheritedDefault */)
 	if err == errNoZoneConfigApplies {
 		// TODO(benesch): This shouldn't be the caller's responsibility;
@@ -115,22 +244,233 @@ func (n *showZoneConfigNode) startExec(params runParams) error {
 		zone = &defZone
 		zoneID = keys.RootNamespaceID
 	} else if err != nil {
-		return err
+		return 0, nil, tree.ZoneSpecifier{}, err
 	} else if subzone != nil {
 		zone = &subzone.Config
 	}
 
 	// Determine the CLI specifier for the zone config that actually applies
 	// without performing another KV lookup.
-	zs := ascendZoneSpecifier(n.zoneSpecifier, uint32(targetID), zoneID, subzone)
+	zs := ascendZoneSpecifier(zoneSpecifier, uint32(targetID), zoneID, subzone)
+	return zoneID, zone, zs, nil
+}
 
-	// Ensure subzone configs don't infect the output of config_bytes.
-	zone.Subzones = nil
-	zone.SubzoneSpans = nil
+var explainZoneConfigColumns = sqlbase.ResultColumns{
+	{Name: "zone_name", Typ: types.String},
+	{Name: "explanation", Typ: types.String},
+}
 
-	n.run.values = make(tree.Datums, len(showZoneConfigNodeColumns))
-	return generateZoneConfigIntrospectionValues(
-		n.run.values, tree.NewDInt(tree.DInt(zoneID)), &zs, zone)
+var explainZoneConfigVerboseColumns = sqlbase.ResultColumns{
+	{Name: "level", Typ: types.String},
+	{Name: "zone_name", Typ: types.String},
+	{Name: "set_fields", Typ: types.String},
+	{Name: "explanation", Typ: types.String},
+}
+
+// ExplainZoneConfig returns an EXPLAIN ZONE CONFIGURATION statement, which
+// describes in plain English where the zone that applies to the specified
+// object places replicas and leases, relative to the gateway's locality.
+//
+// In verbose mode, the statement instead returns one row for each level of the
+// zone hierarchy that the object inherits from, outermost first, listing the
+// fields set at that level and explaining the zone that applies there.
+func (p *planner) ExplainZoneConfig(
+	ctx context.Context, n *tree.ExplainZoneConfig,
+) (planNode, error) {
+	if n.Verbose {
+		return p.explainZoneConfigVerbose(n), nil
+	}
+	return &delayedNode{
+		name:    n.String(),
+		columns: explainZoneConfigColumns,
+
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			_, zone, zs, err := p.getZoneConfigForSpecifier(ctx, n.ZoneSpecifier)
+			if err != nil {
+				return nil, err
+			}
+
+			v := p.newContainerValuesNode(explainZoneConfigColumns, 1)
+			row := tree.Datums{
+				tree.NewDString(config.CLIZoneSpecifier(&zs)),
+				tree.NewDString(cat.ZoneConfigExplain(zone, p.ExecCfg().Locality)),
+			}
+			if _, err := v.rows.AddRow(ctx, row); err != nil {
+				v.Close(ctx)
+				return nil, err
+			}
+			return v, nil
+		},
+	}, nil
+}
+
+func (p *planner) explainZoneConfigVerbose(n *tree.ExplainZoneConfig) planNode {
+	return &delayedNode{
+		name:    n.String(),
+		columns: explainZoneConfigVerboseColumns,
+
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			chain, err := p.zoneInheritanceChain(ctx, n.ZoneSpecifier)
+			if err != nil {
+				return nil, err
+			}
+
+			v := p.newContainerValuesNode(explainZoneConfigVerboseColumns, len(chain))
+			var effective config.ZoneConfig
+			for i := range chain {
+				level := &chain[i]
+				setFields := tree.DNull
+				if level.Zone != nil {
+					zone := *level.Zone.(*config.ZoneConfig)
+					if i != 0 {
+						zone.InheritFromParent(effective)
+					}
+					effective = zone
+					setFields = tree.NewDString(strings.Join(level.SetFields, ", "))
+				}
+				row := tree.Datums{
+					tree.NewDString(level.Level),
+					tree.NewDString(level.Name),
+					setFields,
+					tree.NewDString(cat.ZoneConfigExplain(&effective, p.ExecCfg().Locality)),
+				}
+				if _, err := v.rows.AddRow(ctx, row); err != nil {
+					v.Close(ctx)
+					return nil, err
+				}
+			}
+			return v, nil
+		},
+	}
+}
+
+// zoneInheritanceChain returns the levels of the zone hierarchy that the object
+// named by the zone specifier inherits its zone from, ordered from the cluster
+// default zone down to the object itself. Levels with no zone of their own are
+// included, with a nil Zone.
+//
+// Like ascendZoneSpecifier, this function encodes knowledge about the zone
+// lookup hierarchy, and so must be kept in sync with GetZoneConfig.
+func (p *planner) zoneInheritanceChain(
+	ctx context.Context, zoneSpecifier tree.ZoneSpecifier,
+) ([]cat.ZoneLevel, error) {
+	tblDesc, err := p.resolveTableForZone(ctx, &zoneSpecifier)
+	if err != nil {
+		return nil, err
+	}
+
+	targetID, err := resolveZone(ctx, p.txn, &zoneSpecifier)
+	if err != nil {
+		return nil, err
+	}
+
+	index, partition, err := resolveSubzone(ctx, p.txn, &zoneSpecifier, targetID, tblDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	var chain []cat.ZoneLevel
+	addLevel := func(level string, zs tree.ZoneSpecifier, zone *config.ZoneConfig) {
+		l := cat.ZoneLevel{Level: level, Name: config.CLIZoneSpecifier(&zs)}
+		if zone != nil && !zone.IsSubzonePlaceholder() {
+			l.Zone = zone
+			l.SetFields = zoneConfigSetFields(zone)
+		}
+		chain = append(chain, l)
+	}
+
+	defZone, err := getZoneConfigRaw(ctx, p.txn, keys.RootNamespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if defZone == nil {
+		zone := config.DefaultZoneConfig()
+		defZone = &zone
+	}
+	addLevel("cluster", tree.ZoneSpecifier{NamedZone: config.DefaultZoneName}, defZone)
+	if targetID == keys.RootNamespaceID {
+		return chain, nil
+	}
+
+	if zoneSpecifier.NamedZone != "" {
+		zone, err := getZoneConfigRaw(ctx, p.txn, targetID)
+		if err != nil {
+			return nil, err
+		}
+		addLevel("range", zoneSpecifier, zone)
+		return chain, nil
+	}
+
+	dbID, dbName := targetID, zoneSpecifier.Database
+	if tblDesc != nil {
+		dbID, dbName = tblDesc.ParentID, zoneSpecifier.TableOrIndex.Table.CatalogName
+	}
+	dbZone, err := getZoneConfigRaw(ctx, p.txn, dbID)
+	if err != nil {
+		return nil, err
+	}
+	addLevel("database", tree.ZoneSpecifier{Database: dbName}, dbZone)
+	if tblDesc == nil {
+		return chain, nil
+	}
+
+	tableZone, err := getZoneConfigRaw(ctx, p.txn, tblDesc.ID)
+	if err != nil {
+		return nil, err
+	}
+	zs := tree.ZoneSpecifier{TableOrIndex: tree.TableIndexName{Table: zoneSpecifier.TableOrIndex.Table}}
+	addLevel("table", zs, tableZone)
+	if index == nil {
+		return chain, nil
+	}
+
+	// Look up the subzones exactly, since GetSubzone falls back from a
+	// partition to its index.
+	getSubzone := func(partition string) *config.ZoneConfig {
+		if tableZone == nil {
+			return nil
+		}
+		for i := range tableZone.Subzones {
+			s := &tableZone.Subzones[i]
+			if s.IndexID == uint32(index.ID) && s.PartitionName == partition {
+				return &s.Config
+			}
+		}
+		return nil
+	}
+	zs.TableOrIndex.Index = tree.UnrestrictedName(index.Name)
+	addLevel("index", zs, getSubzone(""))
+	if partition != "" {
+		zs.Partition = tree.Name(partition)
+		addLevel("partition", zs, getSubzone(partition))
+	}
+	return chain, nil
+}
+
+// zoneConfigSetFields returns the names of the fields that are set in the given
+// zone rather than inherited from its parent, in the order that SHOW ZONE
+// CONFIGURATION lists them.
+func zoneConfigSetFields(zone *config.ZoneConfig) []string {
+	var fields []string
+	if zone.RangeMinBytes != nil {
+		fields = append(fields, "range_min_bytes")
+	}
+	if zone.RangeMaxBytes != nil {
+		fields = append(fields, "range_max_bytes")
+	}
+	if zone.GC != nil {
+		fields = append(fields, "gc.ttlseconds")
+	}
+	if zone.NumReplicas != nil {
+		fields = append(fields, "num_replicas")
+	}
+	if !zone.InheritedConstraints {
+		fields = append(fields, "constraints")
+	}
+	if !zone.InheritedLeasePreferences {
+		fields = append(fields, "lease_preferences")
+	}
+	return fields
 }
 
 // generateZoneConfigIntrospectionValues creates a result row
@@ -164,51 +504,21 @@ func generateZoneConfigIntrospectionValues(
 	if zs == nil {
 		values[configSQLCol] = tree.DNull
 	} else {
-		constraints, err := yamlMarshalFlow(config.ConstraintsList{
-			Constraints: zone.Constraints,
-			Inherited:   zone.InheritedConstraints})
-		if err != nil {
-			return err
-		}
-		constraints = strings.TrimSpace(constraints)
-		prefs, err := yamlMarshalFlow(zone.LeasePreferences)
-		if err != nil {
-			return err
-		}
-		prefs = strings.TrimSpace(prefs)
-
-		useComma := false
 		f := tree.NewFmtCtx(tree.FmtParsable)
 		f.WriteString("ALTER ")
-		f.FormatNode(zs)
-		f.WriteString(" CONFIGURE ZONE USING\n")
-		if zone.RangeMinBytes != nil {
-			f.Printf("\trange_min_bytes = %d", *zone.RangeMinBytes)
-			useComma = true
-		}
-		if zone.RangeMaxBytes != nil {
-			writeComma(f, useComma)
-			f.Printf("\trange_max_bytes = %d", *zone.RangeMaxBytes)
-			useComma = true
+		if zs.Partition != "" {
+			// Partitions are addressed as PARTITION ... OF TABLE, since
+			// partition names are unique within a table. Omit the index so
+			// that the statement can be parsed.
+			zs := *zs
+			zs.TableOrIndex.Index = ""
+			f.FormatNode(&zs)
+		} else {
+			f.FormatNode(zs)
 		}
-		if zone.GC != nil {
-			writeComma(f, useComma)
-			f.Printf("\tgc.ttlseconds = %d", zone.GC.TTLSeconds)
-			useComma = true
-		}
-		if zone.NumReplicas != nil {
-			writeComma(f, useComma)
-			f.Printf("\tnum_replicas = %d", *zone.NumReplicas)
-			useComma = true
-		}
-		if !zone.InheritedConstraints {
-			writeComma(f, useComma)
-			f.Printf("\tconstraints = %s", lex.EscapeSQLString(constraints))
-			useComma = true
-		}
-		if !zone.InheritedLeasePreferences {
-			writeComma(f, useComma)
-			f.Printf("\tlease_preferences = %s", lex.EscapeSQLString(prefs))
+		f.WriteByte(' ')
+		if err := formatZoneConfigUsing(f, zone); err != nil {
+			return err
 		}
 		values[configSQLCol] = tree.NewDString(f.String())
 	}
@@ -223,6 +533,55 @@ func generateZoneConfigIntrospectionValues(
 	return nil
 }
 
+// formatZoneConfigUsing writes the CONFIGURE ZONE USING clause that sets the
+// fields of the zone config which are not inherited.
+func formatZoneConfigUsing(f *tree.FmtCtx, zone *config.ZoneConfig) error {
+	constraints, err := yamlMarshalFlow(config.ConstraintsList{
+		Constraints: zone.Constraints,
+		Inherited:   zone.InheritedConstraints})
+	if err != nil {
+		return err
+	}
+	constraints = strings.TrimSpace(constraints)
+	prefs, err := yamlMarshalFlow(zone.LeasePreferences)
+	if err != nil {
+		return err
+	}
+	prefs = strings.TrimSpace(prefs)
+
+	useComma := false
+	f.WriteString("CONFIGURE ZONE USING\n")
+	if zone.RangeMinBytes != nil {
+		f.Printf("\trange_min_bytes = %d", *zone.RangeMinBytes)
+		useComma = true
+	}
+	if zone.RangeMaxBytes != nil {
+		writeComma(f, useComma)
+		f.Printf("\trange_max_bytes = %d", *zone.RangeMaxBytes)
+		useComma = true
+	}
+	if zone.GC != nil {
+		writeComma(f, useComma)
+		f.Printf("\tgc.ttlseconds = %d", zone.GC.TTLSeconds)
+		useComma = true
+	}
+	if zone.NumReplicas != nil {
+		writeComma(f, useComma)
+		f.Printf("\tnum_replicas = %d", *zone.NumReplicas)
+		useComma = true
+	}
+	if !zone.InheritedConstraints {
+		writeComma(f, useComma)
+		f.Printf("\tconstraints = %s", lex.EscapeSQLString(constraints))
+		useComma = true
+	}
+	if !zone.InheritedLeasePreferences {
+		writeComma(f, useComma)
+		f.Printf("\tlease_preferences = %s", lex.EscapeSQLString(prefs))
+	}
+	return nil
+}
+
 // Writes a comma followed by a newline if useComma is true.
 func writeComma(f *tree.FmtCtx, useComma bool) {
 	if useComma {
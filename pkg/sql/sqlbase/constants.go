@@ -68,6 +68,7 @@ const (
 	CrdbInternalTableColumnsTableID
 	CrdbInternalTableIndexesTableID
 	CrdbInternalTablesTableID
+	CrdbInternalZoneConfigAuditLogTableID
 	CrdbInternalZonesTableID
 	InformationSchemaID
 	InformationSchemaAdministrableRoleAuthorizationsID
@@ -484,6 +484,13 @@ var varGen = map[string]sessionVar{
 	// See https://www.postgresql.org/docs/10/static/runtime-config-preset.html#GUC-MAX-INDEX-KEYS
 	`max_index_keys`: makeReadOnlyVar("32"),
 
+	// CockroachDB extension.
+	`crdb_locality`: {
+		Get: func(evalCtx *extendedEvalContext) string {
+			return evalCtx.SessionData.Locality.String()
+		},
+	},
+
 	// CockroachDB extension.
 	`node_id`: {
 		Get: func(evalCtx *extendedEvalContext) string {
@@ -22,6 +22,7 @@ import (
 	"strconv"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -57,6 +58,10 @@ type planObserver struct {
 	// attr is invoked for non-expression metadata in each node.
 	attr func(nodeName, fieldName, attr string)
 
+	// zone is invoked for the zone of the index scanned by each node. The zone
+	// is nil if it is not known.
+	zone func(nodeName, fieldName string, zone cat.Zone)
+
 	// leaveNode is invoked upon leaving a tree node.
 	leaveNode func(nodeName string, plan planNode) error
 
@@ -191,6 +196,9 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 				v.observer.attr(name, "hint", fmt.Sprintf("force index @%s", n.specifiedIndex.Name))
 			}
 		}
+		if v.observer.zone != nil {
+			v.observer.zone(name, "zone", n.zone)
+		}
 		if v.observer.spans != nil {
 			v.observer.spans(name, "spans", n.index, n.spans)
 		}
@@ -0,0 +1,150 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// zonesTablePrefix is the prefix shared by all the keys in the primary index of
+// system.zones.
+var zonesTablePrefix = roachpb.Key(encoding.EncodeUvarintAscending(
+	keys.MakeTablePrefix(uint32(keys.ZonesTableID)), uint64(keys.ZonesTablePrimaryIndexID),
+))
+
+// KVZoneConfigStore is a cat.ZoneConfigStore that reads zones from system.zones
+// using a transaction, so the zones it returns are always up to date as of the
+// transaction's timestamp.
+type KVZoneConfigStore struct {
+	txn *client.Txn
+}
+
+var _ cat.ZoneConfigStore = &KVZoneConfigStore{}
+
+// NewKVZoneConfigStore returns a KVZoneConfigStore that issues its reads using
+// the given transaction.
+func NewKVZoneConfigStore(txn *client.Txn) *KVZoneConfigStore {
+	return &KVZoneConfigStore{txn: txn}
+}
+
+// GetTableZone is part of the cat.ZoneConfigStore interface.
+func (s *KVZoneConfigStore) GetTableZone(
+	ctx context.Context, tableID cat.StableID,
+) (cat.Zone, error) {
+	_, zone, _, err := GetZoneConfigInTxn(
+		ctx, s.txn, uint32(tableID), nil /* index */, "" /* partition */, false, /* getInheritedDefault */
+	)
+	if err == errNoZoneConfigApplies {
+		defZone := config.DefaultZoneConfig()
+		return &defZone, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+
+// ListAllZones is part of the cat.ZoneConfigStore interface.
+func (s *KVZoneConfigStore) ListAllZones(ctx context.Context) ([]cat.Zone, error) {
+	kvs, err := s.txn.Scan(ctx, zonesTablePrefix, zonesTablePrefix.PrefixEnd(), 0 /* maxRows */)
+	if err != nil {
+		return nil, err
+	}
+	zones := make([]cat.Zone, 0, len(kvs))
+	for i := range kvs {
+		zone, err := decodeZoneValue(kvs[i].Value)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
+
+// CachedZoneConfigStore is a cat.ZoneConfigStore that reads zones from the
+// SystemConfig gossiped around the cluster. No KV reads are needed, but the
+// zones it returns might be somewhat stale. A CachedZoneConfigStore is not safe
+// for concurrent use.
+type CachedZoneConfigStore struct {
+	cfg   *config.SystemConfig
+	zones *config.ZoneInheritanceResolver
+}
+
+var _ cat.ZoneConfigStore = &CachedZoneConfigStore{}
+
+// NewCachedZoneConfigStore returns a CachedZoneConfigStore that reads zones
+// from the given SystemConfig. The SystemConfig may be nil if it has not yet
+// been received via gossip, in which case every table is given an empty zone.
+func NewCachedZoneConfigStore(cfg *config.SystemConfig) *CachedZoneConfigStore {
+	s := &CachedZoneConfigStore{cfg: cfg}
+	if cfg != nil {
+		s.zones = config.NewZoneInheritanceResolver(cfg)
+	}
+	return s
+}
+
+// GetTableZone is part of the cat.ZoneConfigStore interface.
+func (s *CachedZoneConfigStore) GetTableZone(
+	ctx context.Context, tableID cat.StableID,
+) (cat.Zone, error) {
+	if s.cfg == nil {
+		return emptyZoneConfig, nil
+	}
+	zone, err := s.zones.ResolveEffectiveZone(uint32(tableID), 0 /* indexID */, "" /* partitionName */)
+	if err != nil {
+		return nil, err
+	}
+	if zone == nil {
+		// This can happen with tests that override the hook.
+		return emptyZoneConfig, nil
+	}
+	return zone, nil
+}
+
+// ListAllZones is part of the cat.ZoneConfigStore interface.
+func (s *CachedZoneConfigStore) ListAllZones(ctx context.Context) ([]cat.Zone, error) {
+	if s.cfg == nil {
+		return nil, nil
+	}
+	var zones []cat.Zone
+	for i := range s.cfg.Values {
+		kv := &s.cfg.Values[i]
+		if !bytes.HasPrefix(kv.Key, zonesTablePrefix) {
+			continue
+		}
+		zone, err := decodeZoneValue(&kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
+
+// decodeZoneValue decodes the zone config stored in a system.zones value.
+func decodeZoneValue(val *roachpb.Value) (*config.ZoneConfig, error) {
+	var zone config.ZoneConfig
+	if err := val.GetProto(&zone); err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/tests"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -642,6 +643,72 @@ func TestCascadingZoneConfig(t *testing.T) {
 	})
 }
 
+// TestZoneConfigStores verifies that the KV and cached zone config stores
+// agree with each other.
+func TestZoneConfigStores(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	params, _ := tests.CreateTestServerParams()
+	srv, sqlDB, kvDB := serverutils.StartServer(t, params)
+	defer srv.Stopper().Stop(context.TODO())
+	s := srv.(*server.TestServer)
+
+	if _, err := sqlDB.Exec(`
+CREATE DATABASE db;
+CREATE TABLE db.t1 (k INT PRIMARY KEY);
+CREATE TABLE db.t2 (k INT PRIMARY KEY);
+ALTER TABLE db.t1 CONFIGURE ZONE USING num_replicas = 7, constraints = '[+region=east]';
+`); err != nil {
+		t.Fatal(err)
+	}
+	t1 := sqlbase.GetTableDescriptor(kvDB, "db", "t1")
+	t2 := sqlbase.GetTableDescriptor(kvDB, "db", "t2")
+	cfg := forceNewConfig(t, s)
+
+	verifyStore := func(name string, store cat.ZoneConfigStore) {
+		zone, err := store.GetTableZone(context.Background(), cat.StableID(t1.ID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := zone.(*config.ZoneConfig).NumReplicas; n == nil || *n != 7 {
+			t.Errorf("%s: expected t1 to have 7 replicas, got %v", name, n)
+		}
+		if summary := cat.ZoneConstraintSummary(zone); summary != "[+region=east]" {
+			t.Errorf("%s: expected t1 to have constraints [+region=east], got %s", name, summary)
+		}
+
+		// t2 has no zone of its own, so it inherits the default zone.
+		zone, err = store.GetTableZone(context.Background(), cat.StableID(t2.ID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := zone.(*config.ZoneConfig).NumReplicas; n == nil || *n != 3 {
+			t.Errorf("%s: expected t2 to have 3 replicas, got %v", name, n)
+		}
+
+		zones, err := store.ListAllZones(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, zone := range zones {
+			if cat.ZoneConstraintSummary(zone) == "[+region=east]" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: expected t1's zone to be listed, got %v", name, zones)
+		}
+	}
+
+	verifyStore("cached", sql.NewCachedZoneConfigStore(cfg))
+	if err := s.DB().Txn(context.Background(), func(ctx context.Context, txn *client.Txn) error {
+		verifyStore("kv", sql.NewKVZoneConfigStore(txn))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func BenchmarkGetZoneConfig(b *testing.B) {
 	defer leaktest.AfterTest(b)()
 
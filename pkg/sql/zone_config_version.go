@@ -0,0 +1,76 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// ZoneConfigVersion is a counter that is incremented each time the zones in the
+// gossiped system config change. The optimizer records the version when it
+// builds a query, so that cached plans can be invalidated by any zone config
+// change without having to compare the zones of every table in the plan.
+//
+// The counter is local to the node, so different nodes may report different
+// versions for the same zones.
+type ZoneConfigVersion struct {
+	// version is accessed atomically.
+	version uint64
+
+	// zones are the zone entries of the last system config passed to update.
+	// They are only accessed by update, which is not called concurrently.
+	zones []roachpb.KeyValue
+}
+
+// Get returns the current version. A nil ZoneConfigVersion always has version
+// zero.
+func (v *ZoneConfigVersion) Get() uint64 {
+	if v == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&v.version)
+}
+
+// update increments the version if the zones in the given system config differ
+// from the zones in the previous one. It must not be called concurrently.
+func (v *ZoneConfigVersion) update(cfg *config.SystemConfig) {
+	if v == nil || cfg == nil {
+		return
+	}
+
+	// The system config values are sorted by key, so the zones are contiguous.
+	var zones []roachpb.KeyValue
+	for i := range cfg.Values {
+		if bytes.HasPrefix(cfg.Values[i].Key, zonesTablePrefix) {
+			zones = append(zones, cfg.Values[i])
+		} else if len(zones) != 0 {
+			break
+		}
+	}
+
+	changed := len(zones) != len(v.zones)
+	for i := 0; !changed && i < len(zones); i++ {
+		changed = !zones[i].Key.Equal(v.zones[i].Key) ||
+			!bytes.Equal(zones[i].Value.RawBytes, v.zones[i].Value.RawBytes)
+	}
+	if changed {
+		v.zones = zones
+		atomic.AddUint64(&v.version, 1)
+	}
+}
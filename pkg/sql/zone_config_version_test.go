@@ -0,0 +1,78 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestZoneConfigVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	zoneKV := func(id uint32, numReplicas int32) roachpb.KeyValue {
+		zone := config.ZoneConfig{NumReplicas: &numReplicas}
+		kv := roachpb.KeyValue{Key: encoding.EncodeUvarintAscending(zonesTablePrefix, uint64(id))}
+		if err := kv.Value.SetProto(&zone); err != nil {
+			t.Fatal(err)
+		}
+		return kv
+	}
+	otherKV := func(value string) roachpb.KeyValue {
+		kv := roachpb.KeyValue{Key: keys.MakeTablePrefix(uint32(keys.DescriptorTableID))}
+		kv.Value.SetString(value)
+		return kv
+	}
+	makeConfig := func(kvs ...roachpb.KeyValue) *config.SystemConfig {
+		cfg := config.NewSystemConfig()
+		cfg.Values = kvs
+		return cfg
+	}
+
+	var v ZoneConfigVersion
+	testCases := []struct {
+		cfg      *config.SystemConfig
+		expected uint64
+	}{
+		// No zones, so nothing has changed.
+		{cfg: makeConfig(otherKV("a")), expected: 0},
+		{cfg: makeConfig(otherKV("a"), zoneKV(0, 3)), expected: 1},
+		// Changes to other tables do not affect the version.
+		{cfg: makeConfig(otherKV("b"), zoneKV(0, 3)), expected: 1},
+		{cfg: makeConfig(otherKV("b"), zoneKV(0, 3), zoneKV(50, 5)), expected: 2},
+		{cfg: makeConfig(otherKV("b"), zoneKV(0, 3), zoneKV(50, 7)), expected: 3},
+		{cfg: makeConfig(otherKV("b"), zoneKV(0, 3), zoneKV(50, 7)), expected: 3},
+		// Removing a zone changes the version.
+		{cfg: makeConfig(otherKV("b"), zoneKV(0, 3)), expected: 4},
+		{cfg: nil, expected: 4},
+	}
+	for i, tc := range testCases {
+		v.update(tc.cfg)
+		if actual := v.Get(); actual != tc.expected {
+			t.Errorf("%d: expected version %d, got %d", i, tc.expected, actual)
+		}
+	}
+
+	var nilVersion *ZoneConfigVersion
+	nilVersion.update(makeConfig(zoneKV(0, 3)))
+	if actual := nilVersion.Get(); actual != 0 {
+		t.Errorf("expected nil version to be 0, got %d", actual)
+	}
+}
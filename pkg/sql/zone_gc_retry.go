@@ -0,0 +1,125 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/pkg/errors"
+)
+
+// zoneGCRetryEnabled controls whether AS OF SYSTEM TIME reads that run into a
+// GC threshold are retried at a newer timestamp. The results of a retried read
+// come from a newer snapshot than the one requested, so this is off by
+// default.
+var zoneGCRetryEnabled = settings.RegisterBoolSetting(
+	"sql.as_of_system_time.gc_threshold_retry.enabled",
+	"if enabled, AS OF SYSTEM TIME reads that run into a GC threshold are retried "+
+		"once at a newer timestamp that the zones of the tables read still retain",
+	false,
+)
+
+// retryWithZoneGCTTL determines whether stmt, an AS OF SYSTEM TIME read that
+// failed with err, can be retried at a newer timestamp. This is the case when
+// err is a BatchTimestampBeforeGCError and the zone of the table being read
+// still retains data at or after the GC threshold that the read ran into: the
+// returned timestamp is the later of the threshold and the start of the zone's
+// GC window (now minus its GC TTL), and it must be before now.
+//
+// Only SELECT statements are retried, since for them reading at a newer
+// timestamp than requested only changes which snapshot the results come from.
+//
+// Errors that come back from remote DistSQL flows have been converted to
+// pgerrors and are not recognized.
+func retryWithZoneGCTTL(
+	err error, zone *config.ZoneConfig, stmt Statement, now hlc.Timestamp,
+) (hlc.Timestamp, bool) {
+	gcErr, ok := errors.Cause(err).(*roachpb.BatchTimestampBeforeGCError)
+	if !ok {
+		return hlc.Timestamp{}, false
+	}
+	if _, ok := stmt.AST.(*tree.Select); !ok || zone.GC == nil {
+		return hlc.Timestamp{}, false
+	}
+
+	retryTS := gcErr.Threshold.Next()
+	gcWindowStart := now.Add(-int64(zone.GC.TTLSeconds)*int64(time.Second), 0)
+	if retryTS.Less(gcWindowStart) {
+		retryTS = gcWindowStart
+	}
+	if !retryTS.Less(now) || !gcErr.Timestamp.Less(retryTS) {
+		return hlc.Timestamp{}, false
+	}
+	return retryTS, true
+}
+
+// zoneGCRetryTimestamp returns the timestamp at which the current AS OF SYSTEM
+// TIME statement can be retried after it failed with err, as determined by
+// retryWithZoneGCTTL for the zones of each of the tables the statement reads.
+// The returned timestamp falls within the GC window of all of them.
+func (ex *connExecutor) zoneGCRetryTimestamp(p *planner, err error) (hlc.Timestamp, bool) {
+	if len(p.curPlan.historicalTables) == 0 {
+		return hlc.Timestamp{}, false
+	}
+	cfg := ex.server.cfg.Gossip.GetSystemConfig()
+	if cfg == nil {
+		return hlc.Timestamp{}, false
+	}
+
+	now := ex.server.cfg.Clock.Now()
+	var retryTS hlc.Timestamp
+	for _, id := range p.curPlan.historicalTables {
+		zone, zoneErr := cfg.GetZoneConfigForObject(uint32(id))
+		if zoneErr != nil {
+			return hlc.Timestamp{}, false
+		}
+		ts, ok := retryWithZoneGCTTL(err, zone, *p.stmt, now)
+		if !ok {
+			return hlc.Timestamp{}, false
+		}
+		if retryTS.Less(ts) {
+			retryTS = ts
+		}
+	}
+	return retryTS, true
+}
+
+// historicalReadTables returns the IDs of the tables scanned by the plan,
+// including by its subqueries.
+func historicalReadTables(ctx context.Context, plan *planTop) []sqlbase.ID {
+	var ids []sqlbase.ID
+	observer := planObserver{
+		enterNode: func(_ context.Context, _ string, n planNode) (bool, error) {
+			if scan, ok := n.(*scanNode); ok {
+				ids = append(ids, scan.desc.ID)
+			}
+			return true, nil
+		},
+	}
+	_ = walkPlan(ctx, plan.plan, observer)
+	for i := range plan.subqueryPlans {
+		if plan.subqueryPlans[i].plan != nil {
+			_ = walkPlan(ctx, plan.subqueryPlans[i].plan, observer)
+		}
+	}
+	return ids
+}
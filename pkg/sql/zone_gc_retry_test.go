@@ -0,0 +1,136 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/pkg/errors"
+)
+
+func TestRetryWithZoneGCTTL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	now := hlc.Timestamp{WallTime: int64(100 * time.Second)}
+	at := func(seconds int) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: int64(seconds) * int64(time.Second)}
+	}
+	zoneWithTTL := func(seconds int32) *config.ZoneConfig {
+		return &config.ZoneConfig{GC: &config.GCPolicy{TTLSeconds: seconds}}
+	}
+	gcErr := func(ts, threshold hlc.Timestamp) error {
+		return errors.Wrap(&roachpb.BatchTimestampBeforeGCError{Timestamp: ts, Threshold: threshold}, "scan")
+	}
+	sel := Statement{Statement: parser.Statement{AST: &tree.Select{}}}
+
+	testCases := []struct {
+		err      error
+		zone     *config.ZoneConfig
+		stmt     Statement
+		expected hlc.Timestamp
+		ok       bool
+	}{
+		// The zone retains data from 50s on, which is after the threshold.
+		{gcErr(at(10), at(20)), zoneWithTTL(50), sel, at(50), true},
+		// The zone retains data from before the threshold.
+		{gcErr(at(10), at(60)), zoneWithTTL(50), sel, at(60).Next(), true},
+		// The zone retains no data before now.
+		{gcErr(at(10), at(20)), zoneWithTTL(0), sel, hlc.Timestamp{}, false},
+		// Other errors are not retried.
+		{errors.New("boom"), zoneWithTTL(50), sel, hlc.Timestamp{}, false},
+		// Only SELECT statements are retried.
+		{
+			gcErr(at(10), at(20)), zoneWithTTL(50),
+			Statement{Statement: parser.Statement{AST: &tree.Delete{}}}, hlc.Timestamp{}, false,
+		},
+	}
+	for i, tc := range testCases {
+		ts, ok := retryWithZoneGCTTL(tc.err, tc.zone, tc.stmt, now)
+		if ok != tc.ok || ts != tc.expected {
+			t.Errorf("%d: expected (%s, %t), got (%s, %t)", i, tc.expected, tc.ok, ts, ok)
+		}
+	}
+}
+
+// TestZoneGCRetry checks that an AS OF SYSTEM TIME read that runs into a GC
+// threshold is only retried at a newer timestamp if the cluster setting is
+// enabled.
+func TestZoneGCRetry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Scans of the table below the threshold fail like they would if the
+	// table's data had been garbage collected. This is simpler than running GC.
+	var tablePrefix atomic.Value
+	tablePrefix.Store(roachpb.Key(nil))
+	var thresholdWallTime int64
+	params := base.TestServerArgs{}
+	params.Knobs.Store = &storage.StoreTestingKnobs{
+		TestingRequestFilter: func(ba roachpb.BatchRequest) *roachpb.Error {
+			threshold := hlc.Timestamp{WallTime: atomic.LoadInt64(&thresholdWallTime)}
+			prefix := tablePrefix.Load().(roachpb.Key)
+			if prefix == nil || !ba.Timestamp.Less(threshold) {
+				return nil
+			}
+			req, ok := ba.GetArg(roachpb.Scan)
+			if !ok || !bytes.HasPrefix(req.Header().Key, prefix) {
+				return nil
+			}
+			return roachpb.NewError(&roachpb.BatchTimestampBeforeGCError{
+				Timestamp: ba.Timestamp, Threshold: threshold,
+			})
+		},
+	}
+	s, db, kvDB := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(context.TODO())
+	db.SetMaxOpenConns(1)
+	r := sqlutils.MakeSQLRunner(db)
+
+	// Retries are only recognized for errors that do not come back from remote
+	// DistSQL flows.
+	r.Exec(t, `SET distsql = off`)
+	r.Exec(t, `CREATE DATABASE d; CREATE TABLE d.t (k INT PRIMARY KEY)`)
+	desc := sqlbase.GetTableDescriptor(kvDB, "d", "t")
+	tablePrefix.Store(roachpb.Key(keys.MakeTablePrefix(uint32(desc.ID))))
+
+	var before string
+	r.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&before)
+	r.Exec(t, `INSERT INTO d.t VALUES (1)`)
+	atomic.StoreInt64(&thresholdWallTime, s.Clock().Now().WallTime)
+
+	query := fmt.Sprintf(`SELECT count(*) FROM d.t AS OF SYSTEM TIME '%s'`, before)
+	r.ExpectErr(t, "must be after replica GC threshold", query)
+
+	// With the setting enabled, the read is retried at the threshold, after the
+	// insert.
+	r.Exec(t, `SET CLUSTER SETTING sql.as_of_system_time.gc_threshold_retry.enabled = true`)
+	r.CheckQueryResults(t, query, [][]string{{"1"}})
+}
@@ -587,6 +587,15 @@ func (r *Replica) GetMaxBytes() int64 {
 	return *r.mu.zone.RangeMaxBytes
 }
 
+// GetSplitBytes gets the size above which the replica's range is split, which
+// is below the maximum byte threshold for zones with small ranges. See
+// ZoneConstraintFillFactor.
+func (r *Replica) GetSplitBytes() int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return zoneSplitBytes(r.mu.zone)
+}
+
 // SetZoneConfig sets the replica's zone config.
 func (r *Replica) SetZoneConfig(zone *config.ZoneConfig) {
 	r.mu.Lock()
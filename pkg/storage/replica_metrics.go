@@ -242,7 +242,8 @@ func (r *Replica) needsSplitBySize() bool {
 }
 
 func (r *Replica) needsSplitBySizeRLocked() bool {
-	return r.exceedsMultipleOfSplitSizeRLocked(1)
+	splitBytes := zoneSplitBytes(r.mu.zone)
+	return splitBytes > 0 && r.mu.state.Stats.Total() > splitBytes
 }
 
 func (r *Replica) needsMergeBySizeRLocked() bool {
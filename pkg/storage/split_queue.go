@@ -109,13 +109,13 @@ func shouldSplitRange(
 
 // shouldQueue determines whether a range should be queued for
 // splitting. This is true if the range is intersected by a zone config
-// prefix or if the range's size in bytes exceeds the limit for the zone,
-// or if the range has too much load on it.
+// prefix or if the range's size in bytes exceeds the split size for the
+// zone, or if the range has too much load on it.
 func (sq *splitQueue) shouldQueue(
 	ctx context.Context, now hlc.Timestamp, repl *Replica, sysCfg *config.SystemConfig,
 ) (shouldQ bool, priority float64) {
 	shouldQ, priority = shouldSplitRange(repl.Desc(), repl.GetMVCCStats(),
-		repl.GetMaxBytes(), sysCfg)
+		repl.GetSplitBytes(), sysCfg)
 
 	if !shouldQ && repl.SplitByLoadEnabled() {
 		if splitKey := repl.loadBasedSplitter.MaybeSplitKey(timeutil.Now()); splitKey != nil {
@@ -177,17 +177,17 @@ func (sq *splitQueue) processAttempt(
 	}
 
 	// Next handle case of splitting due to size. Note that we don't perform
-	// size-based splitting if maxBytes is 0 (happens in certain test
+	// size-based splitting if splitBytes is 0 (happens in certain test
 	// situations).
 	size := r.GetMVCCStats().Total()
-	maxBytes := r.GetMaxBytes()
-	if maxBytes > 0 && float64(size)/float64(maxBytes) > 1 {
+	splitBytes := r.GetSplitBytes()
+	if splitBytes > 0 && float64(size)/float64(splitBytes) > 1 {
 		_, err := r.adminSplitWithDescriptor(
 			ctx,
 			roachpb.AdminSplitRequest{},
 			desc,
 			false, /* delayable */
-			fmt.Sprintf("%s above threshold size %s", humanizeutil.IBytes(size), humanizeutil.IBytes(maxBytes)),
+			fmt.Sprintf("%s above threshold size %s", humanizeutil.IBytes(size), humanizeutil.IBytes(splitBytes)),
 		)
 		return err
 	}
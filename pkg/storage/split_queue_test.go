@@ -62,8 +62,13 @@ func TestSplitQueueShouldQueue(t *testing.T) {
 		{roachpb.RKeyMin, roachpb.RKey(keys.MetaMax), 64<<20 + 1, 64 << 20, true, 1},
 		// No intersection, max bytes * 2, no load.
 		{roachpb.RKeyMin, roachpb.RKey(keys.MetaMax), 64 << 21, 64 << 20, true, 2},
-		// Intersection, max bytes +1, no load.
-		{keys.MakeTablePrefix(2000), roachpb.RKeyMax, 32<<20 + 1, 32 << 20, true, 2},
+		// Intersection, max bytes +1, no load. Ranges of zones with a small
+		// range_max_bytes are split early, at 28 MB for 32 MB.
+		{keys.MakeTablePrefix(2000), roachpb.RKeyMax, 32<<20 + 1, 32 << 20, true, 1 + float64(32<<20+1)/(28<<20)},
+		// No intersection, split bytes of a small zone, no load.
+		{keys.MakeTablePrefix(2002), roachpb.RKeyMax, 28 << 20, 32 << 20, false, 0},
+		// No intersection, split bytes+1 of a small zone, no load.
+		{keys.MakeTablePrefix(2002), roachpb.RKeyMax, 28<<20 + 1, 32 << 20, true, float64(28<<20+1) / (28 << 20)},
 		// Split needed at table boundary, but no zone config, no load.
 		{keys.MakeTablePrefix(2001), roachpb.RKeyMax, 32<<20 + 1, 64 << 20, true, 1},
 	}
@@ -95,7 +100,7 @@ func TestSplitQueueShouldQueue(t *testing.T) {
 		// Testing using shouldSplitRange instead of shouldQueue to avoid using the splitFinder
 		// This tests the merge queue behavior too as a result. For splitFinder tests,
 		// see split/split_test.go.
-		shouldQ, priority := shouldSplitRange(repl.Desc(), repl.GetMVCCStats(), repl.GetMaxBytes(), cfg)
+		shouldQ, priority := shouldSplitRange(repl.Desc(), repl.GetMVCCStats(), repl.GetSplitBytes(), cfg)
 		if shouldQ != test.shouldQ {
 			t.Errorf("%d: should queue expected %t; got %t", i, test.shouldQ, shouldQ)
 		}
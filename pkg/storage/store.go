@@ -376,6 +376,7 @@ type Store struct {
 	allocator          Allocator            // Makes allocation decisions
 	replRankings       *replicaRankings
 	storeRebalancer    *StoreRebalancer
+	zoneDriftMonitor   *ZoneConfigDriftMonitor
 	rangeIDAlloc       *idalloc.Allocator          // Range ID allocator
 	gcQueue            *gcQueue                    // Garbage collection queue
 	mergeQueue         *mergeQueue                 // Range merging queue
@@ -1395,6 +1396,8 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 		s.storeRebalancer = NewStoreRebalancer(
 			s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue, s.replRankings)
 		s.storeRebalancer.Start(ctx, s.stopper)
+		s.zoneDriftMonitor = NewZoneConfigDriftMonitor(s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue)
+		s.zoneDriftMonitor.Start(ctx, s.stopper)
 	}
 
 	// Start the storage engine compactor.
@@ -21,6 +21,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -59,18 +60,26 @@ var (
 		Measurement: "Range Rebalances",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaStoreRebalancerZoneConstraintScore = metric.Metadata{
+		Name:        "zone.constraint_score",
+		Help:        "Fraction of replicas on the store whose zone constraints allow them to be placed on it",
+		Measurement: "Replicas",
+		Unit:        metric.Unit_PERCENT,
+	}
 )
 
 // StoreRebalancerMetrics is the set of metrics for the store-level rebalancer.
 type StoreRebalancerMetrics struct {
 	LeaseTransferCount  *metric.Counter
 	RangeRebalanceCount *metric.Counter
+	ZoneConstraintScore *metric.GaugeFloat64
 }
 
 func makeStoreRebalancerMetrics() StoreRebalancerMetrics {
 	return StoreRebalancerMetrics{
 		LeaseTransferCount:  metric.NewCounter(metaStoreRebalancerLeaseTransferCount),
 		RangeRebalanceCount: metric.NewCounter(metaStoreRebalancerRangeRebalanceCount),
+		ZoneConstraintScore: metric.NewGaugeFloat64(metaStoreRebalancerZoneConstraintScore),
 	}
 }
 
@@ -153,6 +162,7 @@ func NewStoreRebalancer(
 		},
 	}
 	sr.AddLogTag("store-rebalancer", nil)
+	sr.metrics.ZoneConstraintScore.Update(1)
 	sr.rq.store.metrics.registry.AddMetricStruct(&sr.metrics)
 	return sr
 }
@@ -191,6 +201,8 @@ func (sr *StoreRebalancer) Start(ctx context.Context, stopper *stop.Stopper) {
 				timer.Reset(jitteredInterval(storeRebalancerTimerDuration))
 			}
 
+			sr.updateZoneConstraintScore(ctx)
+
 			if !sr.st.Version.IsActive(cluster.VersionLoadBasedRebalancing) {
 				continue
 			}
@@ -206,6 +218,49 @@ func (sr *StoreRebalancer) Start(ctx context.Context, stopper *stop.Stopper) {
 	})
 }
 
+// updateZoneConstraintScore sets the zone.constraint_score gauge to the
+// fraction of the store's replicas whose zone constraints are satisfied by the
+// store. A score below 1 means that some ranges have replicas on the store
+// that their zone configs do not allow there.
+func (sr *StoreRebalancer) updateZoneConstraintScore(ctx context.Context) {
+	localDesc, err := sr.rq.store.Descriptor(true /* useCached */)
+	if err != nil {
+		log.Warningf(ctx, "unable to compute zone constraint score: %v", err)
+		return
+	}
+	var replicas, satisfied int
+	sr.rq.store.VisitReplicas(func(repl *Replica) bool {
+		desc, zone := repl.DescAndZone()
+		replicas++
+		if replicaConstraintsSatisfied(
+			ctx, *localDesc, sr.rq.allocator.storePool.getStoreDescriptor, desc.Replicas, zone,
+		) {
+			satisfied++
+		}
+		return true
+	})
+	score := 1.0
+	if replicas > 0 {
+		score = float64(satisfied) / float64(replicas)
+	}
+	sr.metrics.ZoneConstraintScore.Update(score)
+}
+
+// replicaConstraintsSatisfied returns true if the zone constraints of a range
+// with the given replicas allow it to have a replica on the store, which is
+// the case if the store satisfies one of the sets of constraints or if the
+// zone leaves some replicas unconstrained.
+func replicaConstraintsSatisfied(
+	ctx context.Context,
+	store roachpb.StoreDescriptor,
+	getStoreDescFn func(roachpb.StoreID) (roachpb.StoreDescriptor, bool),
+	existing []roachpb.ReplicaDescriptor,
+	zone *config.ZoneConfig,
+) bool {
+	valid, _ := removeConstraintsCheck(store, analyzeConstraints(ctx, getStoreDescFn, existing, zone))
+	return valid
+}
+
 func (sr *StoreRebalancer) rebalanceStore(
 	ctx context.Context, mode LBRebalancingMode, storeList StoreList,
 ) {
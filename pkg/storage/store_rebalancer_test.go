@@ -380,3 +380,63 @@ func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 			targets, sr.getRaftStatusFn(repl), expectTargets)
 	}
 }
+
+func TestReplicaConstraintsSatisfied(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stores := map[roachpb.StoreID]roachpb.StoreDescriptor{}
+	var existing []roachpb.ReplicaDescriptor
+	for i, attr := range []string{"ssd", "ssd", "hdd"} {
+		storeID := roachpb.StoreID(i + 1)
+		stores[storeID] = roachpb.StoreDescriptor{
+			StoreID: storeID,
+			Attrs:   roachpb.Attributes{Attrs: []string{attr}},
+			Node:    roachpb.NodeDescriptor{NodeID: roachpb.NodeID(storeID)},
+		}
+		existing = append(existing, roachpb.ReplicaDescriptor{
+			NodeID:  roachpb.NodeID(storeID),
+			StoreID: storeID,
+		})
+	}
+	getStoreDesc := func(storeID roachpb.StoreID) (roachpb.StoreDescriptor, bool) {
+		desc, ok := stores[storeID]
+		return desc, ok
+	}
+	ssd := config.Constraint{Value: "ssd", Type: config.Constraint_REQUIRED}
+
+	testCases := []struct {
+		zone     config.ZoneConfig
+		expected []bool
+	}{
+		{
+			zone:     config.ZoneConfig{NumReplicas: proto.Int32(3)},
+			expected: []bool{true, true, true},
+		},
+		{
+			zone: config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{{Constraints: []config.Constraint{ssd}}},
+			},
+			expected: []bool{true, true, false},
+		},
+		{
+			// The third replica is unconstrained, so it may be on any store.
+			zone: config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{{NumReplicas: 2, Constraints: []config.Constraint{ssd}}},
+			},
+			expected: []bool{true, true, true},
+		},
+	}
+
+	for i, tc := range testCases {
+		for j, expected := range tc.expected {
+			if actual := replicaConstraintsSatisfied(
+				ctx, stores[existing[j].StoreID], getStoreDesc, existing, &tc.zone,
+			); actual != expected {
+				t.Errorf("%d: s%d: expected %t, got %t", i, existing[j].StoreID, expected, actual)
+			}
+		}
+	}
+}
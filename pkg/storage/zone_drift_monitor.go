@@ -0,0 +1,187 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// zoneDriftSampleSize is the maximum number of ranges that the
+// ZoneConfigDriftMonitor of a store checks each interval.
+const zoneDriftSampleSize = 64
+
+// zoneDriftMonitorInterval is the interval at which each store checks a sample
+// of the ranges it holds the lease for against their zone constraints.
+var zoneDriftMonitorInterval = settings.RegisterNonNegativeDurationSetting(
+	"kv.zone_drift_monitor.interval",
+	"the interval at which each store checks a sample of its leaseholder ranges "+
+		"against their zone constraints (0 to disable)",
+	10*time.Minute,
+)
+
+var metaZoneDriftRanges = metric.Metadata{
+	Name:        "zone.drift_ranges",
+	Help:        "Number of sampled ranges found with a replica on a store that their zone constraints do not allow",
+	Measurement: "Ranges",
+	Unit:        metric.Unit_COUNT,
+}
+
+// ZoneConfigDriftMetrics is the set of metrics for the zone config drift
+// monitor.
+type ZoneConfigDriftMetrics struct {
+	DriftRanges *metric.Counter
+}
+
+func makeZoneConfigDriftMetrics() ZoneConfigDriftMetrics {
+	return ZoneConfigDriftMetrics{
+		DriftRanges: metric.NewCounter(metaZoneDriftRanges),
+	}
+}
+
+// ZoneConfigDriftMonitor periodically checks a random sample of the ranges
+// whose leases are held by a store against their zone constraints. Node
+// additions and decommissions, as well as zone config changes, can leave
+// replicas on stores that the zone constraints of their range no longer
+// allow. The replicate queue moves such replicas when it gets to their range
+// during the store's periodic scan, which can take a long time on stores with
+// many ranges. The monitor finds some of them sooner, counts them in the
+// zone.drift_ranges metric and offers them to the replicate queue right away.
+//
+// Only ranges whose leases are held by the store are sampled, so that each
+// range is checked by a single store.
+type ZoneConfigDriftMonitor struct {
+	log.AmbientContext
+	metrics ZoneConfigDriftMetrics
+	st      *cluster.Settings
+	rq      *replicateQueue
+}
+
+// NewZoneConfigDriftMonitor creates a ZoneConfigDriftMonitor for the store of
+// the given replicate queue.
+func NewZoneConfigDriftMonitor(
+	ambientCtx log.AmbientContext, st *cluster.Settings, rq *replicateQueue,
+) *ZoneConfigDriftMonitor {
+	m := &ZoneConfigDriftMonitor{
+		AmbientContext: ambientCtx,
+		metrics:        makeZoneConfigDriftMetrics(),
+		st:             st,
+		rq:             rq,
+	}
+	m.AddLogTag("zone-drift", nil)
+	m.rq.store.metrics.registry.AddMetricStruct(&m.metrics)
+	return m
+}
+
+// Start runs a goroutine which checks a sample of the store's ranges every
+// kv.zone_drift_monitor.interval.
+func (m *ZoneConfigDriftMonitor) Start(ctx context.Context, stopper *stop.Stopper) {
+	ctx = m.AnnotateCtx(ctx)
+
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		timer := timeutil.NewTimer()
+		defer timer.Stop()
+		for {
+			interval := zoneDriftMonitorInterval.Get(&m.st.SV)
+			if interval == 0 {
+				// Check again later in case the monitor is enabled.
+				interval = time.Minute
+			}
+			timer.Reset(jitteredInterval(interval))
+			select {
+			case <-stopper.ShouldQuiesce():
+				return
+			case <-timer.C:
+				timer.Read = true
+			}
+
+			if zoneDriftMonitorInterval.Get(&m.st.SV) == 0 {
+				continue
+			}
+			m.checkSample(ctx)
+		}
+	})
+}
+
+// checkSample checks a random sample of up to zoneDriftSampleSize of the
+// ranges whose leases are held by the store.
+func (m *ZoneConfigDriftMonitor) checkSample(ctx context.Context) {
+	store := m.rq.store
+	now := store.Clock().Now()
+
+	// Reservoir sampling picks each leaseholder replica with equal
+	// probability.
+	var sample []*Replica
+	var seen int
+	store.VisitReplicas(func(repl *Replica) bool {
+		if !repl.OwnsValidLease(now) {
+			return true
+		}
+		seen++
+		if len(sample) < zoneDriftSampleSize {
+			sample = append(sample, repl)
+		} else if i := rand.Intn(seen); i < zoneDriftSampleSize {
+			sample[i] = repl
+		}
+		return true
+	})
+
+	for _, repl := range sample {
+		desc, zone := repl.DescAndZone()
+		storeID, ok := driftedStore(ctx, m.rq.allocator.storePool.getStoreDescriptor, desc.Replicas, zone)
+		if !ok {
+			continue
+		}
+		m.metrics.DriftRanges.Inc(1)
+		log.Warningf(ctx, "r%d has a replica on s%d that violates its zone constraints, "+
+			"enqueuing it in the replicate queue", repl.RangeID, storeID)
+		m.rq.MaybeAdd(repl, now)
+	}
+}
+
+// driftedStore returns a store with one of the existing replicas of a range
+// that the range's zone constraints do not allow it to have a replica on, if
+// there is one. Stores whose descriptors are not known are assumed to be
+// allowed.
+func driftedStore(
+	ctx context.Context,
+	getStoreDescFn func(roachpb.StoreID) (roachpb.StoreDescriptor, bool),
+	existing []roachpb.ReplicaDescriptor,
+	zone *config.ZoneConfig,
+) (roachpb.StoreID, bool) {
+	if len(zone.Constraints) == 0 {
+		return 0, false
+	}
+	for _, replica := range existing {
+		storeDesc, ok := getStoreDescFn(replica.StoreID)
+		if !ok {
+			continue
+		}
+		if !replicaConstraintsSatisfied(ctx, storeDesc, getStoreDescFn, existing, zone) {
+			return replica.StoreID, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestDriftedStore(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stores := map[roachpb.StoreID]roachpb.StoreDescriptor{}
+	var existing []roachpb.ReplicaDescriptor
+	for i, region := range []string{"east", "east", "west"} {
+		storeID := roachpb.StoreID(i + 1)
+		stores[storeID] = roachpb.StoreDescriptor{
+			StoreID: storeID,
+			Node: roachpb.NodeDescriptor{
+				NodeID:   roachpb.NodeID(storeID),
+				Locality: roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: region}}},
+			},
+		}
+		existing = append(existing, roachpb.ReplicaDescriptor{
+			NodeID:  roachpb.NodeID(storeID),
+			StoreID: storeID,
+		})
+	}
+	getStoreDesc := func(storeID roachpb.StoreID) (roachpb.StoreDescriptor, bool) {
+		desc, ok := stores[storeID]
+		return desc, ok
+	}
+	inRegion := func(region string) config.Constraint {
+		return config.Constraint{Type: config.Constraint_REQUIRED, Key: "region", Value: region}
+	}
+
+	testCases := []struct {
+		zone     config.ZoneConfig
+		expected roachpb.StoreID
+	}{
+		{zone: config.ZoneConfig{NumReplicas: proto.Int32(3)}},
+		{
+			zone: config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{{Constraints: []config.Constraint{inRegion("east")}}},
+			},
+			expected: 3,
+		},
+		{
+			zone: config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{
+					{NumReplicas: 2, Constraints: []config.Constraint{inRegion("east")}},
+					{NumReplicas: 1, Constraints: []config.Constraint{inRegion("west")}},
+				},
+			},
+		},
+		{
+			zone: config.ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				Constraints: []config.Constraints{{Constraints: []config.Constraint{inRegion("west")}}},
+			},
+			expected: 1,
+		},
+	}
+
+	for i, tc := range testCases {
+		storeID, ok := driftedStore(ctx, getStoreDesc, existing, &tc.zone)
+		if ok != (tc.expected != 0) || storeID != tc.expected {
+			t.Errorf("%d: expected s%d, got s%d (%t)", i, tc.expected, storeID, ok)
+		}
+	}
+
+	// Stores whose descriptors are not known are assumed to be allowed.
+	delete(stores, 3)
+	if storeID, ok := driftedStore(ctx, getStoreDesc, existing, &testCases[1].zone); ok {
+		t.Errorf("expected no drifted store, got s%d", storeID)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import "github.com/cockroachdb/cockroach/pkg/config"
+
+const (
+	// zoneFillFactorMaxBytesThreshold is the range_max_bytes below which ranges
+	// are split before they reach their zone's size limit.
+	zoneFillFactorMaxBytesThreshold = 64 << 20 // 64 MB
+
+	// minZoneFillFactor is the fill factor of ranges in zones with the smallest
+	// range_max_bytes.
+	minZoneFillFactor = 0.75
+)
+
+// ZoneConstraintFillFactor returns the fraction of the zone's range_max_bytes
+// that a range in the zone is allowed to fill before it is split. Ranges keep
+// growing between the time they pass their split threshold and the time the
+// split queue splits them, which matters more the smaller range_max_bytes is:
+// a few batches of writes can take a small range well past its limit, which
+// leads to repeated splits and oversized ranges being backpressured. Zones with
+// a range_max_bytes below zoneFillFactorMaxBytesThreshold get a fill factor that
+// decreases linearly down to minZoneFillFactor, so that their ranges are split
+// earlier. All other zones have a fill factor of 1.
+func ZoneConstraintFillFactor(zone *config.ZoneConfig) float64 {
+	if zone.RangeMaxBytes == nil {
+		return 1
+	}
+	maxBytes := *zone.RangeMaxBytes
+	if maxBytes <= 0 || maxBytes >= zoneFillFactorMaxBytesThreshold {
+		return 1
+	}
+	return minZoneFillFactor +
+		(1-minZoneFillFactor)*float64(maxBytes)/zoneFillFactorMaxBytesThreshold
+}
+
+// zoneSplitBytes returns the size above which ranges in the zone are split.
+func zoneSplitBytes(zone *config.ZoneConfig) int64 {
+	return int64(float64(*zone.RangeMaxBytes) * ZoneConstraintFillFactor(zone))
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestZoneConstraintFillFactor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		maxBytes   *int64
+		fillFactor float64
+		splitBytes int64
+	}{
+		{nil, 1, 0},
+		{proto.Int64(0), 1, 0},
+		{proto.Int64(128 << 20), 1, 128 << 20},
+		{proto.Int64(64 << 20), 1, 64 << 20},
+		{proto.Int64(32 << 20), 0.875, 28 << 20},
+		{proto.Int64(16 << 20), 0.8125, 13 << 20},
+	}
+	for _, c := range testCases {
+		zone := &config.ZoneConfig{RangeMaxBytes: c.maxBytes}
+		if fillFactor := ZoneConstraintFillFactor(zone); fillFactor != c.fillFactor {
+			t.Errorf("%v: expected fill factor %f, got %f", c.maxBytes, c.fillFactor, fillFactor)
+		}
+		if c.maxBytes == nil {
+			continue
+		}
+		if splitBytes := zoneSplitBytes(zone); splitBytes != c.splitBytes {
+			t.Errorf("%d: expected split bytes %d, got %d", *c.maxBytes, c.splitBytes, splitBytes)
+		}
+	}
+}
@@ -382,6 +382,13 @@ func runRun(gen workload.Generator, urls []string, dbName string) error {
 		}
 	}()
 
+	var zoneIssues chan string
+	var numZoneIssues int
+	if *zoneLintInterval > 0 {
+		zoneIssues = make(chan string)
+		go runZoneLint(workersCtx, initDB, *zoneLintInterval, zoneIssues)
+	}
+
 	var numErr int
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
@@ -446,6 +453,11 @@ func runRun(gen workload.Generator, urls []string, dbName string) error {
 				}
 			})
 
+		case issue := <-zoneIssues:
+			numZoneIssues++
+			log.Warningf(ctx, "zone config issue: %s; "+
+				"latencies may not be representative of a correctly configured cluster", issue)
+
 		// Once the load generator is fully ramped up, we reset the histogram
 		// and the start time to throw away the stats for the the ramp up period.
 		case <-rampDone:
@@ -507,6 +519,10 @@ func runRun(gen workload.Generator, urls []string, dbName string) error {
 			fmt.Println(totalHeader + `__result`)
 			printTotalHist(resultTick)
 
+			if numZoneIssues > 0 {
+				fmt.Printf("\nwarning: found %d zone config issue(s) during the run\n", numZoneIssues)
+			}
+
 			if h, ok := gen.(workload.Hookser); ok {
 				if h.Hooks().PostRun != nil {
 					if err := h.Hooks().PostRun(startElapsed); err != nil {
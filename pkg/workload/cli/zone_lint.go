@@ -0,0 +1,123 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"context"
+	gosql "database/sql"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonelint"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+var zoneLintInterval = runFlags.Duration("zone-lint-interval", 0,
+	"How often to check the zone configs for problems, like constraints that the nodes "+
+		"cannot satisfy, which would make latencies unrepresentative of a correctly "+
+		"configured cluster. If 0, never.")
+
+// runZoneLint checks the zone configs of the cluster every interval with the
+// checks of `cockroach zone lint`, and sends each issue to issues the first
+// time it is found. An issue that persists is only reported once, so the
+// number of issues sent is the number of distinct problems found during the
+// run. It returns when the context is canceled.
+func runZoneLint(ctx context.Context, db *gosql.DB, interval time.Duration, issues chan<- string) {
+	seen := make(map[string]struct{})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		found, err := lintZones(ctx, db)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warningf(ctx, "unable to check zone configs: %v", err)
+		}
+		for _, issue := range newZoneIssues(seen, found) {
+			select {
+			case issues <- issue:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// newZoneIssues returns the issues that are not in seen, and adds them to it.
+func newZoneIssues(seen map[string]struct{}, issues []string) []string {
+	var res []string
+	for _, issue := range issues {
+		if _, ok := seen[issue]; !ok {
+			seen[issue] = struct{}{}
+			res = append(res, issue)
+		}
+	}
+	return res
+}
+
+// lintZones returns the issues that zonelint.Lint finds in the zone configs of
+// the cluster.
+func lintZones(ctx context.Context, db *gosql.DB) ([]string, error) {
+	var zones []zonelint.Zone
+	rows, err := db.QueryContext(ctx, zonelint.ZonesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var configProto []byte
+		if err := rows.Scan(&name, &configProto); err != nil {
+			return nil, err
+		}
+		z, err := zonelint.MakeZone(name, configProto)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, z)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var localities []roachpb.Locality
+	localityRows, err := db.QueryContext(ctx, zonelint.LocalitiesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer localityRows.Close()
+	for localityRows.Next() {
+		var localityJSON string
+		if err := localityRows.Scan(&localityJSON); err != nil {
+			return nil, err
+		}
+		locality, err := zonelint.ParseLocality(localityJSON)
+		if err != nil {
+			return nil, err
+		}
+		localities = append(localities, locality)
+	}
+	if err := localityRows.Err(); err != nil {
+		return nil, err
+	}
+	return zonelint.Lint(zones, localities), nil
+}
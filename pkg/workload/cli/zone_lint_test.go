@@ -0,0 +1,45 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestNewZoneIssues(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	seen := make(map[string]struct{})
+	checks := []struct {
+		found    []string
+		expected []string
+	}{
+		{nil, nil},
+		{[]string{"a", "b"}, []string{"a", "b"}},
+		// Issues that persist are not reported again.
+		{[]string{"a", "b", "c"}, []string{"c"}},
+		{[]string{"b"}, nil},
+		// Nor are issues that come back after they were fixed.
+		{[]string{"a", "d"}, []string{"d"}},
+	}
+	for i, check := range checks {
+		if actual := newZoneIssues(seen, check.found); !reflect.DeepEqual(actual, check.expected) {
+			t.Errorf("%d: expected %q, got %q", i, check.expected, actual)
+		}
+	}
+}